@@ -0,0 +1,268 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"syscall"
+	"time"
+)
+
+// Exit codes returned by App.Run
+const (
+	// ExitOK is returned when the Manager returns without an error
+	ExitOK = 0
+	// ExitFailure is returned when Init or the Manager returns an error
+	ExitFailure = 1
+	// ExitUsage is returned when the flags are invalid
+	ExitUsage = 2
+	// ExitShutdownTimeout is returned when the components are not
+	// shutdown within the shutdown timeout, see ShutdownTimeoutError
+	ExitShutdownTimeout = 3
+)
+
+// App runs a root Manager as a process. It parses the standard flags, handles
+// signals and converts the result of Manager.Run into an exit code. The flags are:
+//
+//	-shutdown-timeout duration   maximum duration of the graceful shutdown
+//	-drain-delay duration        delay between a signal and the start of the shutdown
+//	-log-level level             minimum level of the logs: debug, info, warn or error
+//	-check                       run Init to validate the configuration and exit
+//	-version                     print the build information and exit
+type App struct {
+	// Name is the name of the root Manager and of the flag set, defaults to the executable name
+	Name string
+	// Init adds the components to the root Manager, it's also run in -check mode
+	Init func(ctx context.Context, m *Manager) error
+	// Options are applied to the root Manager after ShutdownTimeout, and before
+	// the -shutdown-timeout flag when it's passed
+	Options []Option
+	// ShutdownTimeout is the default of the -shutdown-timeout flag,
+	// a ShutdownTimeout in Options takes precedence over it
+	ShutdownTimeout time.Duration
+	// DrainDelay is the default of the -drain-delay flag, it gives load balancers time
+	// to stop routing traffic. A second signal skips the remaining delay.
+	DrainDelay time.Duration
+	// Flags is the flag set to which the standard flags are added, so that the
+	// application can define its own flags. It defaults to a new flag set.
+	Flags *flag.FlagSet
+	// Args are the command line arguments, defaults to os.Args[1:]
+	Args []string
+	// Signals trigger the shutdown, defaults to SIGINT and SIGTERM
+	Signals []os.Signal
+	// Stdout receives the output of -version, defaults to os.Stdout
+	Stdout io.Writer
+	// Logger is used by the components through Logger, it defaults to a text
+	// logger writing to os.Stderr at the level of the -log-level flag
+	Logger *slog.Logger
+	// ExitCode overrides the exit code for the error returned by Init or the Manager,
+	// the default codes are used when it returns a negative value
+	ExitCode func(error) int
+	// OnShutdown receives the ShutdownReport of the root Manager once it has returned
+	OnShutdown func(ShutdownReport)
+}
+
+// Main runs the App and exits the process with the exit code
+func (a *App) Main() {
+	os.Exit(a.Run())
+}
+
+// Run runs the App and returns the exit code of the process
+func (a *App) Run() int {
+	name := a.Name
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+
+	f, err := a.parseFlags(name)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+
+		return ExitUsage
+	}
+
+	if f.version {
+		a.printVersion(name)
+
+		return ExitOK
+	}
+
+	logger := a.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: f.level}))
+	}
+
+	m := NewManager(a.options(name, logger, f)...)
+
+	ctx, stop := a.notify(logger, f.drainDelay)
+	defer stop()
+
+	if a.Init != nil {
+		if err := a.Init(ctx, m); err != nil {
+			logger.Error("init failed", "error", err)
+
+			return a.exitCode(err)
+		}
+	}
+
+	if f.check {
+		logger.Info("configuration is valid")
+
+		return ExitOK
+	}
+
+	err = m.Run(ctx)
+
+	if a.OnShutdown != nil {
+		a.OnShutdown(m.ShutdownReport())
+	}
+
+	if err != nil {
+		logger.Error("run failed", "error", err)
+
+		return a.exitCode(err)
+	}
+
+	return ExitOK
+}
+
+// appFlags holds the values of the standard flags
+type appFlags struct {
+	fs              *flag.FlagSet
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	check           bool
+	version         bool
+	level           slog.Level
+}
+
+// parseFlags adds the standard flags to the flag set and parses the arguments
+func (a *App) parseFlags(name string) (*appFlags, error) {
+	f := &appFlags{fs: a.Flags}
+	if f.fs == nil {
+		f.fs = flag.NewFlagSet(name, flag.ContinueOnError)
+	}
+
+	f.fs.DurationVar(&f.shutdownTimeout, "shutdown-timeout", a.ShutdownTimeout,
+		"maximum duration of the graceful shutdown")
+	f.fs.DurationVar(&f.drainDelay, "drain-delay", a.DrainDelay,
+		"delay between a signal and the start of the shutdown")
+	f.fs.BoolVar(&f.check, "check", false, "run Init to validate the configuration and exit")
+	f.fs.BoolVar(&f.version, "version", false, "print the build information and exit")
+	f.fs.TextVar(&f.level, "log-level", slog.LevelInfo, "minimum level of the logs: debug, info, warn or error")
+
+	args := a.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	return f, f.fs.Parse(args)
+}
+
+// options returns the options of the root Manager
+func (a *App) options(name string, logger *slog.Logger, f *appFlags) []Option {
+	opts := append([]Option{Name(name), WithLogger(logger), ShutdownTimeout(a.ShutdownTimeout)}, a.Options...)
+
+	// the flag overrides Options only when it's passed
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "shutdown-timeout" {
+			opts = append(opts, ShutdownTimeout(f.shutdownTimeout))
+		}
+	})
+
+	return opts
+}
+
+// notify returns a context which is closed after the drain delay once a signal is received
+func (a *App) notify(logger *slog.Logger, drainDelay time.Duration) (context.Context, func()) {
+	signals := a.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, signals...)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer cancel()
+
+		select {
+		case <-done:
+			return
+		case sig := <-sigCh:
+			logger.Info("received signal, shutting down", "signal", sig, "drain_delay", drainDelay)
+		}
+
+		if drainDelay <= 0 {
+			return
+		}
+
+		timer := time.NewTimer(drainDelay)
+		defer timer.Stop()
+
+		select {
+		case <-done:
+		case <-timer.C:
+		case <-sigCh:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigCh)
+		close(done)
+		cancel()
+	}
+}
+
+func (a *App) exitCode(err error) int {
+	if a.ExitCode != nil {
+		if code := a.ExitCode(err); code >= 0 {
+			return code
+		}
+	}
+
+	var tErr *ShutdownTimeoutError
+	if errors.As(err, &tErr) {
+		return ExitShutdownTimeout
+	}
+
+	return ExitFailure
+}
+
+func (a *App) printVersion(name string) {
+	w := a.Stdout
+	if w == nil {
+		w = os.Stdout
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		_, _ = fmt.Fprintf(w, "%s (unknown build)\n", name)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", name, info.Main.Version)
+	_, _ = fmt.Fprintf(w, "  path: %s\n", info.Main.Path)
+	_, _ = fmt.Fprintf(w, "  go: %s\n", info.GoVersion)
+
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", s.Key, s.Value)
+		}
+	}
+}
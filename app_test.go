@@ -0,0 +1,197 @@
+package xrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp(t *testing.T) {
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// slowStop fails and takes 100ms to shutdown
+	slowStop := func(ctx context.Context, m *Manager) error {
+		if err := m.Add(ComponentFunc(func(ctx context.Context) error { return errors.New("boom") })); err != nil {
+			return err
+		}
+
+		return m.Add(ComponentFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(100 * time.Millisecond)
+
+			return nil
+		}))
+	}
+
+	testcases := []struct {
+		name        string
+		args        []string
+		options     []Option
+		init        func(ctx context.Context, m *Manager) error
+		exitCode    func(error) int
+		wantCode    int
+		wantStarted bool
+	}{
+		{
+			name:        "Success",
+			options:     []Option{ExitWhenAllDone(true)},
+			wantCode:    ExitOK,
+			wantStarted: true,
+		},
+		{
+			name:    "ComponentError",
+			options: []Option{ExitWhenAllDone(true)},
+			init: func(ctx context.Context, m *Manager) error {
+				return m.Add(ComponentFunc(func(ctx context.Context) error { return errors.New("boom") }))
+			},
+			wantCode:    ExitFailure,
+			wantStarted: true,
+		},
+		{
+			name:    "CustomExitCode",
+			options: []Option{ExitWhenAllDone(true)},
+			init: func(ctx context.Context, m *Manager) error {
+				return m.Add(ComponentFunc(func(ctx context.Context) error { return errors.New("boom") }))
+			},
+			exitCode:    func(error) int { return 42 },
+			wantCode:    42,
+			wantStarted: true,
+		},
+		{
+			name:     "Check",
+			args:     []string{"-check"},
+			wantCode: ExitOK,
+		},
+		{
+			name:     "InitError",
+			args:     []string{"-check"},
+			init:     func(context.Context, *Manager) error { return errors.New("invalid config") },
+			wantCode: ExitFailure,
+		},
+		{
+			name:     "InvalidFlag",
+			args:     []string{"-log-level", "loud"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:        "ShutdownTimeout",
+			args:        []string{"-shutdown-timeout", "10ms"},
+			options:     []Option{ExitWhenAllDone(true)},
+			init:        slowStop,
+			wantCode:    ExitShutdownTimeout,
+			wantStarted: true,
+		},
+		{
+			name:        "ShutdownTimeoutOption",
+			options:     []Option{ExitWhenAllDone(true), ShutdownTimeout(10 * time.Millisecond)},
+			init:        slowStop,
+			wantCode:    ExitShutdownTimeout,
+			wantStarted: true,
+		},
+		{
+			name:        "ShutdownTimeoutFlagOverridesOption",
+			args:        []string{"-shutdown-timeout", "1s"},
+			options:     []Option{ExitWhenAllDone(true), ShutdownTimeout(10 * time.Millisecond)},
+			init:        slowStop,
+			wantCode:    ExitFailure,
+			wantStarted: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var started atomic.Bool
+
+			init := tc.init
+			if init == nil {
+				init = func(ctx context.Context, m *Manager) error {
+					return m.Add(ComponentFunc(func(ctx context.Context) error { return nil }))
+				}
+			}
+
+			app := &App{
+				Name: "orders",
+				Init: func(ctx context.Context, m *Manager) error {
+					return init(ctx, m)
+				},
+				Options:  append(tc.options, Hooks{OnStateChange: func(ComponentStatus) { started.Store(true) }}),
+				Flags:    flag.NewFlagSet("orders", flag.ContinueOnError),
+				Args:     append([]string{}, tc.args...),
+				Logger:   quiet,
+				ExitCode: tc.exitCode,
+			}
+			app.Flags.SetOutput(io.Discard)
+
+			assert.Equal(t, tc.wantCode, app.Run())
+			assert.Equal(t, tc.wantStarted, started.Load())
+		})
+	}
+}
+
+func TestAppVersion(t *testing.T) {
+	var out bytes.Buffer
+
+	app := &App{Name: "orders", Args: []string{"-version"}, Stdout: &out}
+
+	assert.Equal(t, ExitOK, app.Run())
+	assert.Contains(t, out.String(), "orders ")
+	assert.Contains(t, out.String(), "go: go1.")
+}
+
+func TestAppSignal(t *testing.T) {
+	stopped := make(chan time.Time, 1)
+
+	var report ShutdownReport
+
+	app := &App{
+		Name:       "orders",
+		Args:       []string{"-drain-delay", "100ms"},
+		Signals:    []os.Signal{syscall.SIGHUP},
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		DrainDelay: time.Hour,
+		Init: func(ctx context.Context, m *Manager) error {
+			return m.Add(ComponentFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				stopped <- time.Now()
+
+				return nil
+			}))
+		},
+		OnShutdown: func(r ShutdownReport) { report = r },
+	}
+
+	codeCh := make(chan int, 1)
+	go func() { codeCh <- app.Run() }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	signalled := time.Now()
+	require.NoError(t, p.Signal(syscall.SIGHUP))
+
+	select {
+	case code := <-codeCh:
+		assert.Equal(t, ExitOK, code)
+	case <-time.After(time.Second):
+		t.Fatal("app did not stop on signal")
+	}
+
+	assert.GreaterOrEqual(t, (<-stopped).Sub(signalled), 100*time.Millisecond)
+
+	require.Len(t, report.Components, 1)
+	assert.Equal(t, "orders/component-1", report.Components[0].Path)
+	assert.False(t, report.Components[0].SignalledAt.IsZero())
+}
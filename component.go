@@ -2,6 +2,7 @@ package xrun
 
 import (
 	"context"
+	"fmt"
 )
 
 // Component allows a component to be started.
@@ -25,3 +26,67 @@ type ComponentFunc func(ctx context.Context) error
 func (f ComponentFunc) Run(ctx context.Context) error {
 	return f(ctx)
 }
+
+// Primary marks a Component as primary. Once all the primary components
+// of a Manager have returned, Manager.Run returns and the remaining
+// components are shutdown gracefully.
+func Primary(c Component) Component {
+	return primaryComponent{Component: c}
+}
+
+type primaryComponent struct {
+	Component
+}
+
+// Unwrap returns the underlying Component
+func (p primaryComponent) Unwrap() Component { return p.Component }
+
+func isPrimary(c Component) bool {
+	_, ok := find[primaryComponent](c)
+
+	return ok
+}
+
+// Named gives a Component a name, which is used to refer
+// to it in the Hooks and errors of a Manager
+func Named(name string, c Component) Component {
+	return namedComponent{Component: c, name: name}
+}
+
+type namedComponent struct {
+	Component
+	name string
+}
+
+// Unwrap returns the underlying Component
+func (n namedComponent) Unwrap() Component { return n.Component }
+
+// nameOf returns the name given with Named, or a name based on the
+// position i at which the Component was added to the Manager
+func nameOf(c Component, i int) string {
+	if n, ok := find[namedComponent](c); ok {
+		return n.name
+	}
+
+	return fmt.Sprintf("component-%d", i+1)
+}
+
+// find returns the first Component implementing T in the chain of components wrapping each other
+func find[T any](c Component) (T, bool) {
+	for c != nil {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+
+		u, ok := c.(interface{ Unwrap() Component })
+		if !ok {
+			break
+		}
+
+		c = u.Unwrap()
+	}
+
+	var zero T
+
+	return zero, false
+}
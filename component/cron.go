@@ -0,0 +1,178 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// OverlapPolicy decides what happens when a Cron run is due
+// while the previous run is still in progress
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run which is due
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs the due run once the in progress runs are done
+	OverlapQueue
+	// OverlapAllow starts the due run concurrently
+	OverlapAllow
+)
+
+// CronOptions holds options for Cron
+type CronOptions struct {
+	// Schedule decides when Func runs, see Every and ParseCron
+	Schedule Schedule
+	// Func is called on every activation of Schedule
+	Func func(ctx context.Context) error
+	// Jitter adds a random delay in [0, Jitter) to each activation
+	Jitter time.Duration
+	// Overlap decides how runs which are due while a run is in progress are handled
+	Overlap OverlapPolicy
+	// Location is the time zone used to evaluate Schedule, defaults to time.Local
+	Location *time.Location
+	// Timeout limits the duration of each run, zero means no limit
+	Timeout time.Duration
+	// WaitOnStop lets in-flight runs finish on shutdown instead of cancelling them
+	WaitOnStop bool
+	// OnError is called with the errors returned by Func
+	OnError func(error)
+}
+
+// Cron is a helper which returns an xrun.ComponentFunc to run a function on a Schedule
+func Cron(opts CronOptions) xrun.ComponentFunc {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return func(ctx context.Context) error {
+		if opts.Schedule == nil || opts.Func == nil {
+			return errors.New("cron: both Schedule and Func are required")
+		}
+
+		xrun.Ready(ctx)
+
+		parent := ctx
+		if opts.WaitOnStop {
+			// runs keep the values of ctx, such as xrun.Logger
+			parent = context.WithoutCancel(ctx)
+		}
+
+		runCtx, cancelRuns := context.WithCancel(parent)
+		defer cancelRuns()
+
+		r := &cronRunner{opts: opts, stop: ctx.Done()}
+		defer r.wg.Wait()
+
+		next := opts.Schedule.Next(time.Now().In(loc))
+
+		for !next.IsZero() {
+			timer := time.NewTimer(time.Until(next) + jitter(opts.Jitter))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return nil
+			case <-timer.C:
+				r.trigger(runCtx)
+			}
+
+			next = opts.Schedule.Next(time.Now().In(loc))
+		}
+
+		<-ctx.Done()
+
+		return nil
+	}
+}
+
+type cronRunner struct {
+	opts CronOptions
+	stop <-chan struct{}
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	running bool
+	queued  int
+}
+
+func (r *cronRunner) trigger(ctx context.Context) {
+	if r.opts.Overlap == OverlapAllow {
+		r.wg.Add(1)
+
+		go func() {
+			defer r.wg.Done()
+			r.run(ctx)
+		}()
+
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		if r.opts.Overlap == OverlapQueue {
+			r.queued++
+		}
+
+		return
+	}
+
+	r.running = true
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		for {
+			r.run(ctx)
+
+			r.mu.Lock()
+			if r.queued == 0 || r.stopped() {
+				r.running = false
+				r.mu.Unlock()
+
+				return
+			}
+			r.queued--
+			r.mu.Unlock()
+		}
+	}()
+}
+
+func (r *cronRunner) stopped() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *cronRunner) run(ctx context.Context) {
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
+
+		defer cancel()
+	}
+
+	if err := r.opts.Func(ctx); err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
+
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d)))
+}
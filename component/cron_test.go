@@ -0,0 +1,200 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/gojekfarm/xrun"
+)
+
+type CronSuite struct {
+	suite.Suite
+}
+
+func TestCronSuite(t *testing.T) {
+	suite.Run(t, new(CronSuite))
+}
+
+func (s *CronSuite) TestCron() {
+	testcases := []struct {
+		name     string
+		overlap  OverlapPolicy
+		runFor   time.Duration
+		minCalls int32
+		maxCalls int32
+		maxConc  int32
+	}{
+		{name: "Skip", overlap: OverlapSkip, runFor: 70 * time.Millisecond, minCalls: 2, maxCalls: 3, maxConc: 1},
+		{name: "Queue", overlap: OverlapQueue, runFor: 70 * time.Millisecond, minCalls: 3, maxCalls: 4, maxConc: 1},
+		{name: "Allow", overlap: OverlapAllow, runFor: 70 * time.Millisecond, minCalls: 7, maxCalls: 11, maxConc: 4},
+	}
+
+	for _, t := range testcases {
+		s.Run(t.name, func() {
+			var calls, running, maxRunning atomic.Int32
+
+			c := Cron(CronOptions{
+				Schedule: Every(20 * time.Millisecond),
+				Overlap:  t.overlap,
+				Func: func(ctx context.Context) error {
+					calls.Add(1)
+					n := running.Add(1)
+					defer running.Add(-1)
+
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+
+					select {
+					case <-ctx.Done():
+					case <-time.After(t.runFor):
+					}
+
+					return nil
+				},
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 210*time.Millisecond)
+			defer cancel()
+
+			s.NoError(c.Run(ctx))
+			s.GreaterOrEqual(calls.Load(), t.minCalls)
+			s.LessOrEqual(calls.Load(), t.maxCalls)
+			s.LessOrEqual(maxRunning.Load(), t.maxConc)
+		})
+	}
+}
+
+func (s *CronSuite) TestCronOptions() {
+	s.Run("MissingSchedule", func() {
+		s.Error(Cron(CronOptions{Func: func(ctx context.Context) error { return nil }}).Run(context.Background()))
+	})
+
+	s.Run("Timeout", func() {
+		errCh := make(chan error, 1)
+
+		c := Cron(CronOptions{
+			Schedule: Every(10 * time.Millisecond),
+			Timeout:  20 * time.Millisecond,
+			Func: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			OnError: func(err error) {
+				select {
+				case errCh <- err:
+				default:
+				}
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			s.ErrorIs(<-errCh, context.DeadlineExceeded)
+			cancel()
+		}()
+
+		s.NoError(c.Run(ctx))
+	})
+
+	s.Run("WaitOnStop", func() {
+		var (
+			finished atomic.Bool
+			value    atomic.Value
+		)
+
+		started := make(chan struct{})
+		once := sync.Once{}
+
+		c := Cron(CronOptions{
+			Schedule:   Every(10 * time.Millisecond),
+			WaitOnStop: true,
+			Func: func(ctx context.Context) error {
+				once.Do(func() { close(started) })
+				value.Store(ctx.Value(cronKey{}))
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+					finished.Store(true)
+					return nil
+				}
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), cronKey{}, "value"))
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		s.NoError(c.Run(ctx))
+		s.True(finished.Load())
+		s.Equal("value", value.Load())
+	})
+
+	s.Run("CancelOnStop", func() {
+		var runErr atomic.Value
+
+		started := make(chan struct{})
+		once := sync.Once{}
+
+		c := Cron(CronOptions{
+			Schedule: Every(10 * time.Millisecond),
+			Func: func(ctx context.Context) error {
+				once.Do(func() { close(started) })
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			OnError: func(err error) { runErr.Store(err) },
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		s.NoError(c.Run(ctx))
+		s.True(errors.Is(runErr.Load().(error), context.Canceled))
+	})
+
+	s.Run("Jitter", func() {
+		for i := 0; i < 100; i++ {
+			d := jitter(10 * time.Millisecond)
+			s.GreaterOrEqual(d, time.Duration(0))
+			s.Less(d, 10*time.Millisecond)
+		}
+		s.Zero(jitter(0))
+	})
+}
+
+func (s *CronSuite) TestCronWithManager() {
+	var calls atomic.Int32
+
+	m := xrun.NewManager()
+	s.NoError(m.Add(Cron(CronOptions{
+		Schedule: MustParseCron("@every 10ms"),
+		Func: func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	})))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	s.NoError(m.Run(ctx))
+	s.Greater(calls.Load(), int32(0))
+}
+
+type cronKey struct{}
@@ -2,11 +2,14 @@ package component_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"time"
 
+	"github.com/gojekfarm/xrun"
 	"github.com/gojekfarm/xrun/component"
 )
 
@@ -28,3 +31,165 @@ func ExampleHTTPServer() {
 		os.Exit(1)
 	}
 }
+
+func ExampleCron() {
+	c := component.Cron(component.CronOptions{
+		Schedule: component.MustParseCron("*/5 * * * *"),
+		Func: func(ctx context.Context) error {
+			fmt.Println("cleaning up expired sessions")
+			return nil
+		},
+		Overlap:    component.OverlapSkip,
+		Timeout:    time.Minute,
+		WaitOnStop: true,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleTicker() {
+	c := component.Ticker(30*time.Second, func(ctx context.Context) error {
+		fmt.Println("refreshing cache")
+		return nil
+	}, component.TickerOptions{
+		Immediate:            true,
+		MaxBackoff:           5 * time.Minute,
+		MaxConsecutiveErrors: 10,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleWorkerPool() {
+	jobs := make(chan string)
+
+	p := component.NewWorkerPool(component.WorkerPoolOptions[string]{
+		Source: component.ChannelSource(jobs),
+		Handler: func(ctx context.Context, job string) error {
+			fmt.Println("processing", job)
+			return nil
+		},
+		Workers:      4,
+		DrainTimeout: 10 * time.Second,
+		OnError: func(job string, err error) {
+			fmt.Println("failed to process", job, err)
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := p.Run(ctx); err != nil {
+		fmt.Println("dropped items:", p.Stats().Dropped)
+		os.Exit(1)
+	}
+}
+
+func ExampleHTTPServer_tls() {
+	c := component.HTTPServer(component.HTTPServerOptions{
+		Server:   &http.Server{Addr: ":8443"},
+		CertFile: "/etc/tls/tls.crt",
+		KeyFile:  "/etc/tls/tls.key",
+		// rotated certificates are picked up without restarting the server
+		CertReloadInterval: time.Minute,
+		OnCertReload: func(err error) {
+			fmt.Println("certificate reloaded, error:", err)
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleHTTPServer_unixSocket() {
+	c := component.HTTPServer(component.HTTPServerOptions{
+		Server:      &http.Server{},
+		NewListener: component.UnixListener("/run/app/http.sock", 0o660),
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleLeaderElected() {
+	c := component.LeaderElected(component.FileLocker{Path: "/var/run/reports.lock"},
+		component.Cron(component.CronOptions{
+			Schedule: component.MustParseCron("@hourly"),
+			Func: func(ctx context.Context) error {
+				fmt.Println("generating reports")
+				return nil
+			},
+		}),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExamplePIDFile() {
+	m := xrun.NewManager(xrun.ShutdownTimeout(30 * time.Second))
+
+	if err := m.Add(component.HTTPServer(component.HTTPServerOptions{Server: &http.Server{}})); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	// the pid file is removed only after all components of m have stopped
+	if err := component.PIDFile("/var/run/app.pid", m).Run(ctx); err != nil {
+		if errors.Is(err, component.ErrAlreadyRunning) {
+			fmt.Println(err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func ExampleExec() {
+	m := xrun.NewManager(xrun.ShutdownTimeout(30 * time.Second))
+
+	if err := m.Add(xrun.Named("envoy", component.Exec(component.ExecOptions{
+		Path:        "envoy",
+		Args:        []string{"-c", "/etc/envoy/envoy.yaml"},
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		GracePeriod: 20 * time.Second,
+	}))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		var exitErr *component.ExitError
+		if errors.As(err, &exitErr) {
+			fmt.Println("envoy exited with code", exitErr.Code)
+		}
+
+		os.Exit(1)
+	}
+}
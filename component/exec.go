@@ -0,0 +1,118 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+const defaultGracePeriod = 10 * time.Second
+
+// ExecOptions holds options for Exec
+type ExecOptions struct {
+	// Path is the command to run, it's looked up in PATH when it contains no separator
+	Path string
+	Args []string
+	// Env is the environment of the command, nil inherits the current environment
+	Env []string
+	// Dir is the working directory of the command, empty uses the current directory
+	Dir string
+	// Stdout and Stderr receive the output of the command, nil discards it
+	Stdout io.Writer
+	Stderr io.Writer
+	// GracePeriod is the time given to the command to exit after SIGTERM,
+	// before it's killed with SIGKILL. It defaults to 10 seconds.
+	GracePeriod time.Duration
+}
+
+// ExitError is returned by Exec when the command exits on its own with a non-zero code
+type ExitError struct {
+	Path string
+	// Code is the exit code, or -1 when the command was terminated by a signal
+	Code int
+
+	err error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exec %s: exited with code %d", e.Path, e.Code)
+}
+
+// Unwrap returns the underlying *exec.ExitError
+func (e *ExitError) Unwrap() error { return e.err }
+
+// Exec is a helper which returns an xrun.ComponentFunc to run an external command.
+// The command runs in its own process group, when the context is closed SIGTERM is sent
+// to the group, followed by SIGKILL once the GracePeriod has passed. Once the command
+// exits, the processes left in its group are killed with SIGKILL. The component signals
+// readiness with xrun.Ready once the command has started, and returns an *ExitError
+// when the command exits on its own with a non-zero code.
+func Exec(opts ExecOptions) xrun.ComponentFunc {
+	grace := opts.GracePeriod
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+
+	return func(ctx context.Context) error {
+		cmd := exec.Command(opts.Path, opts.Args...)
+		cmd.Env = opts.Env
+		cmd.Dir = opts.Dir
+		cmd.Stdout = opts.Stdout
+		cmd.Stderr = opts.Stderr
+		// processes which inherited the output must not keep Wait blocked
+		cmd.WaitDelay = grace
+
+		setProcessGroup(cmd)
+
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		xrun.Ready(ctx)
+
+		waitCh := make(chan error, 1)
+
+		go func() { waitCh <- cmd.Wait() }()
+
+		select {
+		case err := <-waitCh:
+			return errors.Join(exitError(opts.Path, err), killGroup(cmd))
+		case <-ctx.Done():
+		}
+
+		if err := terminate(cmd); err != nil {
+			return errors.Join(err, kill(cmd), <-waitCh)
+		}
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-waitCh:
+			return killGroup(cmd)
+		case <-timer.C:
+		}
+
+		if err := kill(cmd); err != nil {
+			return err
+		}
+
+		<-waitCh
+
+		return fmt.Errorf("exec %s: killed after grace period(%s)", opts.Path, grace)
+	}
+}
+
+func exitError(path string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ExitError{Path: path, Code: exitErr.ExitCode(), err: exitErr}
+	}
+
+	return err
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package component
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(*exec.Cmd) {}
+
+// terminate kills the process as there is no SIGTERM on this platform
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+func kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killGroup is a no-op as there are no process groups on this platform
+func killGroup(*exec.Cmd) error { return nil }
@@ -0,0 +1,193 @@
+//go:build unix
+
+package component
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExec(t *testing.T) {
+	testcases := []struct {
+		name       string
+		script     string
+		grace      time.Duration
+		stopAfter  time.Duration
+		wantErr    string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "StreamsOutput",
+			script:     "echo out; echo err >&2",
+			wantStdout: "out\n",
+			wantStderr: "err\n",
+		},
+		{
+			name:     "ExitCode",
+			script:   "exit 3",
+			wantErr:  "exec sh: exited with code 3",
+			wantCode: 3,
+		},
+		{
+			name:       "GracefulStop",
+			script:     "trap 'echo stopping; exit 0' TERM; while true; do sleep 0.01; done",
+			stopAfter:  100 * time.Millisecond,
+			wantStdout: "stopping\n",
+		},
+		{
+			name:      "KillAfterGracePeriod",
+			script:    "trap '' TERM; while true; do sleep 0.01; done",
+			grace:     100 * time.Millisecond,
+			stopAfter: 100 * time.Millisecond,
+			wantErr:   "exec sh: killed after grace period(100ms)",
+		},
+		{
+			name:      "StopsProcessGroup",
+			script:    "sleep 30 & wait",
+			grace:     5 * time.Second,
+			stopAfter: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := &syncBuffer{}, &syncBuffer{}
+
+			c := Exec(ExecOptions{
+				Path:        "sh",
+				Args:        []string{"-c", tc.script},
+				Stdout:      stdout,
+				Stderr:      stderr,
+				GracePeriod: tc.grace,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.stopAfter > 0 {
+				time.AfterFunc(tc.stopAfter, cancel)
+			}
+
+			start := time.Now()
+			err := c.Run(ctx)
+
+			assert.Less(t, time.Since(start), 2*time.Second)
+
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tc.wantCode != 0 {
+				var exitErr *ExitError
+				assert.True(t, errors.As(err, &exitErr))
+				assert.Equal(t, tc.wantCode, exitErr.Code)
+			}
+
+			assert.Equal(t, tc.wantStdout, stdout.String())
+
+			// the shell may report the termination of its children on stderr
+			if tc.wantStderr != "" {
+				assert.Equal(t, tc.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestExecKillsLeftoverProcesses(t *testing.T) {
+	// the child ignores SIGTERM and outlives the shell
+	child := "(trap '' TERM; exec sleep 30) >/dev/null 2>&1 & echo $!; "
+
+	testcases := []struct {
+		name      string
+		script    string
+		stopAfter time.Duration
+	}{
+		{
+			name:   "Exited",
+			script: child + "exit 0",
+		},
+		{
+			name:      "GracefulStop",
+			script:    child + "trap 'exit 0' TERM; while true; do sleep 0.01; done",
+			stopAfter: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := &syncBuffer{}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.stopAfter > 0 {
+				time.AfterFunc(tc.stopAfter, cancel)
+			}
+
+			assert.NoError(t, Exec(ExecOptions{
+				Path:        "sh",
+				Args:        []string{"-c", tc.script},
+				Stdout:      stdout,
+				GracePeriod: 5 * time.Second,
+			}).Run(ctx))
+
+			pid, err := strconv.Atoi(strings.TrimSpace(stdout.String()))
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool { return exited(pid) }, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+// exited reports whether the process is gone, or is a zombie waiting to be reaped
+func exited(pid int) bool {
+	if errors.Is(syscall.Kill(pid, 0), syscall.ESRCH) {
+		return true
+	}
+
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+
+	return err == nil && strings.Contains(string(stat), ") Z ")
+}
+
+func TestExecStartError(t *testing.T) {
+	err := Exec(ExecOptions{Path: "/does/not/exist"}).Run(context.Background())
+	assert.Error(t, err)
+
+	var exitErr *ExitError
+	assert.False(t, errors.As(err, &exitErr))
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
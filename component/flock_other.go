@@ -0,0 +1,14 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package component
+
+import (
+	"errors"
+	"os"
+)
+
+var errLockUnsupported = errors.New("file locks are not supported on this platform")
+
+func tryLock(*os.File) (bool, error) { return false, errLockUnsupported }
+
+func unlock(*os.File) error { return errLockUnsupported }
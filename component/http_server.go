@@ -2,8 +2,11 @@ package component
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/gojekfarm/xrun"
 )
@@ -13,6 +16,21 @@ type HTTPServerOptions struct {
 	Server   *http.Server
 	PreStart func()
 	PreStop  func()
+	// NewListener creates the listener to serve on, see TCPListener, UnixListener and Listener.
+	// When nil, the server listens on Server.Addr. The component signals readiness
+	// with xrun.Ready once the listener is created.
+	NewListener func() (net.Listener, error)
+	// CertFile and KeyFile enable TLS with the key pair loaded from the files
+	CertFile string
+	KeyFile  string
+	// TLSConfig enables TLS with the provided configuration,
+	// it is used as the base configuration when CertFile and KeyFile are set
+	TLSConfig *tls.Config
+	// CertReloadInterval enables checking CertFile and KeyFile for changes
+	// at the given interval, changed certificates are served without restarting the server
+	CertReloadInterval time.Duration
+	// OnCertReload is called after the certificate is reloaded, with the error if it failed
+	OnCertReload func(error)
 }
 
 // HTTPServer is a helper which returns an xrun.ComponentFunc to start an http.Server
@@ -22,6 +40,11 @@ func HTTPServer(opts HTTPServerOptions) xrun.ComponentFunc {
 	pst := opts.PreStop
 
 	return func(ctx context.Context) error {
+		serve, err := serveFunc(ctx, opts)
+		if err != nil {
+			return err
+		}
+
 		errCh := make(chan error, 1)
 
 		go func() {
@@ -29,7 +52,7 @@ func HTTPServer(opts HTTPServerOptions) xrun.ComponentFunc {
 				ps()
 			}
 
-			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errCh <- err
 			}
 		}()
@@ -50,3 +73,75 @@ func HTTPServer(opts HTTPServerOptions) xrun.ComponentFunc {
 		return srv.Shutdown(shutdownCtx)
 	}
 }
+
+func serveFunc(ctx context.Context, opts HTTPServerOptions) (func() error, error) {
+	srv := opts.Server
+
+	certFile, keyFile, withTLS, err := configureTLS(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	nl := opts.NewListener
+	if nl == nil {
+		nl = TCPListener(defaultAddr(srv.Addr, withTLS))
+	}
+
+	l, err := nl()
+	if err != nil {
+		return nil, err
+	}
+
+	xrun.Ready(ctx)
+
+	if withTLS {
+		return func() error { return srv.ServeTLS(l, certFile, keyFile) }, nil
+	}
+
+	return func() error { return srv.Serve(l) }, nil
+}
+
+// defaultAddr follows the defaults of http.Server.ListenAndServe and http.Server.ListenAndServeTLS
+func defaultAddr(addr string, withTLS bool) string {
+	switch {
+	case addr != "":
+		return addr
+	case withTLS:
+		return ":https"
+	default:
+		return ":http"
+	}
+}
+
+// configureTLS sets up Server.TLSConfig and returns the key pair files to serve with
+func configureTLS(ctx context.Context, opts HTTPServerOptions) (string, string, bool, error) {
+	srv := opts.Server
+
+	if opts.CertFile == "" && opts.KeyFile == "" && opts.TLSConfig == nil {
+		return "", "", false, nil
+	}
+
+	if opts.TLSConfig != nil {
+		srv.TLSConfig = opts.TLSConfig.Clone()
+	}
+
+	if opts.CertReloadInterval <= 0 || opts.CertFile == "" {
+		return opts.CertFile, opts.KeyFile, true, nil
+	}
+
+	r, err := newCertReloader(opts.CertFile, opts.KeyFile, opts.OnCertReload)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	if srv.TLSConfig == nil {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	srv.TLSConfig.Certificates = nil
+	srv.TLSConfig.GetCertificate = r.GetCertificate
+
+	go r.watch(ctx, opts.CertReloadInterval)
+
+	return "", "", true, nil
+}
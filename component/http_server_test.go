@@ -2,8 +2,11 @@ package component
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
+	"net"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -128,3 +131,156 @@ func (s *HTTPServerSuite) TestHTTPServer() {
 		})
 	}
 }
+
+func (s *HTTPServerSuite) TestHTTPServerTLS() {
+	dir := s.T().TempDir()
+	certFile, keyFile := writeKeyPair(s.T(), dir, 1)
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	s.Require().NoError(err)
+
+	testcases := []struct {
+		name       string
+		addr       string
+		opts       HTTPServerOptions
+		wantReload bool
+	}{
+		{
+			name: "CertFiles",
+			addr: "localhost:8443",
+			opts: HTTPServerOptions{CertFile: certFile, KeyFile: keyFile},
+		},
+		{
+			name: "TLSConfig",
+			addr: "localhost:8444",
+			opts: HTTPServerOptions{TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}}},
+		},
+		{
+			name: "CertReload",
+			addr: "localhost:8445",
+			opts: HTTPServerOptions{
+				CertFile:           certFile,
+				KeyFile:            keyFile,
+				CertReloadInterval: 10 * time.Millisecond,
+			},
+			wantReload: true,
+		},
+	}
+
+	for _, t := range testcases {
+		s.Run(t.name, func() {
+			reloaded := make(chan error, 1)
+
+			opts := t.opts
+			opts.Server = &http.Server{Addr: t.addr}
+			opts.OnCertReload = func(err error) { reloaded <- err }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() { errCh <- HTTPServer(opts).Run(ctx) }()
+
+			s.Eventually(func() bool {
+				serial, err := peerSerial(t.addr)
+				return err == nil && serial == 1
+			}, 5*time.Second, 50*time.Millisecond)
+
+			if t.wantReload {
+				writeKeyPair(s.T(), dir, 2)
+				s.NoError(<-reloaded)
+
+				serial, err := peerSerial(t.addr)
+				s.NoError(err)
+				s.Equal(int64(2), serial)
+
+				writeKeyPair(s.T(), dir, 1)
+				s.NoError(<-reloaded)
+			}
+
+			cancel()
+			s.NoError(<-errCh)
+		})
+	}
+
+	s.Run("InvalidCertFiles", func() {
+		s.Error(HTTPServer(HTTPServerOptions{
+			Server:             &http.Server{Addr: "localhost:8446"},
+			CertFile:           "missing.crt",
+			KeyFile:            "missing.key",
+			CertReloadInterval: time.Second,
+		}).Run(context.Background()))
+	})
+}
+
+func (s *HTTPServerSuite) TestHTTPServerWithListener() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+
+	sock := filepath.Join(s.T().TempDir(), "http.sock")
+
+	testcases := []struct {
+		name        string
+		newListener ListenerFunc
+		client      func(addr net.Addr) (*http.Client, string)
+		wantErr     bool
+	}{
+		{
+			name:        "TCP",
+			newListener: TCPListener("localhost:0"),
+			client: func(addr net.Addr) (*http.Client, string) {
+				return http.DefaultClient, "http://" + addr.String() + "/ping"
+			},
+		},
+		{
+			name:        "Unix",
+			newListener: UnixListener(sock, 0o600),
+			client: func(addr net.Addr) (*http.Client, string) {
+				return &http.Client{Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return (&net.Dialer{}).DialContext(ctx, "unix", addr.String())
+					},
+				}}, "http://unix/ping"
+			},
+		},
+		{
+			name:        "ListenerError",
+			newListener: TCPListener(":-1"),
+			wantErr:     true,
+		},
+	}
+
+	for _, t := range testcases {
+		s.Run(t.name, func() {
+			addrCh := make(chan net.Addr, 1)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- HTTPServer(HTTPServerOptions{
+					Server:      &http.Server{Handler: mux},
+					NewListener: NotifyAddr(t.newListener, func(addr net.Addr) { addrCh <- addr }),
+				}).Run(ctx)
+			}()
+
+			if t.wantErr {
+				s.Error(<-errCh)
+				return
+			}
+
+			c, url := t.client(<-addrCh)
+			resp, err := c.Get(url)
+			s.Require().NoError(err)
+
+			d, err := io.ReadAll(resp.Body)
+			s.NoError(err)
+			s.NoError(resp.Body.Close())
+			s.Equal("pong", string(d))
+
+			cancel()
+			s.NoError(<-errCh)
+		})
+	}
+}
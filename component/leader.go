@@ -0,0 +1,215 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// Locker is a backend for leader election, see FileLocker and MemoryLocker.
+// It can be implemented on top of a Kubernetes Lease or an etcd lock.
+type Locker interface {
+	// Lock blocks until the Lease is acquired or the context is closed.
+	// Errors other than the context being closed stop the campaign,
+	// so transient backend errors should be retried by the Locker.
+	Lock(ctx context.Context) (Lease, error)
+}
+
+// Lease grants leadership to the holder until it is lost or released
+type Lease interface {
+	// Lost is closed when the lease is lost, example: it wasn't renewed in time
+	Lost() <-chan struct{}
+	// Release gives up the lease
+	Release() error
+}
+
+// LeaderElected is a helper which returns an xrun.ComponentFunc to run c only while
+// holding a Lease from lock. When the Lease is lost, c is cancelled and campaigning
+// resumes once it returns. The component returns when c returns on its own.
+// It signals readiness with xrun.Ready while campaigning, as a replica which
+// is not the leader is still healthy.
+func LeaderElected(lock Locker, c xrun.Component) xrun.ComponentFunc {
+	return func(ctx context.Context) error {
+		xrun.Ready(ctx)
+
+		for {
+			lease, err := lock.Lock(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return err
+			}
+
+			lost, err := runWhileLeader(ctx, lease, c)
+			if !lost || ctx.Err() != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runWhileLeader runs c until the lease is lost, it reports
+// whether the lease was lost and the errors of c and Release
+func runWhileLeader(ctx context.Context, lease Lease, c xrun.Component) (bool, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lostCh := make(chan bool, 1)
+
+	go func() {
+		select {
+		case <-lease.Lost():
+			lostCh <- true
+
+			cancel()
+		case <-runCtx.Done():
+			lostCh <- false
+		}
+	}()
+
+	err := c.Run(runCtx)
+
+	cancel()
+
+	lost := <-lostCh
+	if lost {
+		err = nil
+	}
+
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+
+	return lost, errors.Join(err, lease.Release())
+}
+
+const defaultLockRetryInterval = time.Second
+
+// FileLocker is a Locker backed by an advisory lock (flock) on the file at Path,
+// it elects a leader among the processes running on the same host
+type FileLocker struct {
+	Path string
+	// RetryInterval is the interval at which the lock is retried, defaults to a second
+	RetryInterval time.Duration
+}
+
+// Lock blocks until the exclusive lock on Path is acquired or the context is closed
+func (l FileLocker) Lock(ctx context.Context) (Lease, error) {
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := l.RetryInterval
+	if retry <= 0 {
+		retry = defaultLockRetryInterval
+	}
+
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		if locked {
+			return fileLease{f: f}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+
+			return nil, ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+type fileLease struct {
+	f *os.File
+}
+
+// Lost never fires as the lock is held until it's released or the process exits
+func (fileLease) Lost() <-chan struct{} { return nil }
+
+func (l fileLease) Release() error {
+	return errors.Join(unlock(l.f), l.f.Close())
+}
+
+// MemoryLocker is a Locker for a single process, useful in tests
+type MemoryLocker struct {
+	mu      sync.Mutex
+	current *memoryLease
+	free    chan struct{}
+}
+
+// NewMemoryLocker creates a MemoryLocker
+func NewMemoryLocker() *MemoryLocker {
+	free := make(chan struct{}, 1)
+	free <- struct{}{}
+
+	return &MemoryLocker{free: free}
+}
+
+// Lock blocks until the lease is released, revoked or the context is closed
+func (l *MemoryLocker) Lock(ctx context.Context) (Lease, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-l.free:
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.current = &memoryLease{locker: l, lost: make(chan struct{})}
+
+	return l.current, nil
+}
+
+// Revoke makes the current holder lose its lease,
+// the lock is free once the holder releases it
+func (l *MemoryLocker) Revoke() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.current != nil && !l.current.revoked {
+		l.current.revoked = true
+		close(l.current.lost)
+	}
+}
+
+// release must be called with mu held
+func (l *MemoryLocker) release(lease *memoryLease) {
+	if l.current != lease {
+		return
+	}
+
+	l.current = nil
+	l.free <- struct{}{}
+}
+
+type memoryLease struct {
+	locker  *MemoryLocker
+	lost    chan struct{}
+	revoked bool
+}
+
+func (m *memoryLease) Lost() <-chan struct{} { return m.lost }
+
+func (m *memoryLease) Release() error {
+	m.locker.mu.Lock()
+	defer m.locker.mu.Unlock()
+
+	m.locker.release(m)
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/gojekfarm/xrun"
+)
+
+type LeaderSuite struct {
+	suite.Suite
+}
+
+func TestLeaderSuite(t *testing.T) {
+	suite.Run(t, new(LeaderSuite))
+}
+
+func (s *LeaderSuite) TestSingleLeader() {
+	lock := NewMemoryLocker()
+
+	var leaders, maxLeaders atomic.Int32
+
+	inner := xrun.ComponentFunc(func(ctx context.Context) error {
+		n := leaders.Add(1)
+		defer leaders.Add(-1)
+
+		if n > maxLeaders.Load() {
+			maxLeaders.Store(n)
+		}
+
+		<-ctx.Done()
+
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- LeaderElected(lock, inner).Run(ctx) }()
+	go func() { errCh <- LeaderElected(lock, inner).Run(ctx) }()
+
+	s.Eventually(func() bool { return leaders.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	// the other replica takes over on lease loss
+	lock.Revoke()
+	s.Eventually(func() bool { return leaders.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.NoError(<-errCh)
+	s.NoError(<-errCh)
+	s.Equal(int32(1), maxLeaders.Load())
+}
+
+func (s *LeaderSuite) TestLeaseLossCancelsAndCampaignsAgain() {
+	lock := NewMemoryLocker()
+
+	var runs atomic.Int32
+
+	inner := xrun.ComponentFunc(func(ctx context.Context) error {
+		runs.Add(1)
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- LeaderElected(lock, inner).Run(ctx) }()
+
+	s.Eventually(func() bool { return runs.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	lock.Revoke()
+	s.Eventually(func() bool { return runs.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	s.NoError(<-errCh)
+}
+
+func (s *LeaderSuite) TestInnerReturns() {
+	lock := NewMemoryLocker()
+
+	err := LeaderElected(lock, xrun.ComponentFunc(func(ctx context.Context) error {
+		return errors.New("inner failed")
+	})).Run(context.Background())
+	s.EqualError(err, "inner failed")
+
+	// the lease is released
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	lease, err := lock.Lock(ctx)
+	s.NoError(err)
+	s.NoError(lease.Release())
+}
+
+func (s *LeaderSuite) TestLockError() {
+	err := LeaderElected(failingLocker{}, xrun.ComponentFunc(func(ctx context.Context) error {
+		s.Fail("must not run")
+
+		return nil
+	})).Run(context.Background())
+	s.EqualError(err, "backend unavailable")
+}
+
+func (s *LeaderSuite) TestFileLocker() {
+	path := filepath.Join(s.T().TempDir(), "leader.lock")
+
+	first := FileLocker{Path: path, RetryInterval: 10 * time.Millisecond}
+	second := FileLocker{Path: path, RetryInterval: 10 * time.Millisecond}
+
+	lease, err := first.Lock(context.Background())
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = second.Lock(ctx)
+	s.ErrorIs(err, context.DeadlineExceeded)
+
+	s.NoError(lease.Release())
+
+	lease, err = second.Lock(context.Background())
+	s.Require().NoError(err)
+	s.NoError(lease.Release())
+}
+
+type failingLocker struct{}
+
+func (failingLocker) Lock(context.Context) (Lease, error) {
+	return nil, errors.New("backend unavailable")
+}
@@ -0,0 +1,97 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+)
+
+// ListenerFunc creates the net.Listener a server component serves on
+type ListenerFunc func() (net.Listener, error)
+
+// TCPListener returns a ListenerFunc which listens on the TCP address
+func TCPListener(addr string) ListenerFunc {
+	return func() (net.Listener, error) {
+		return net.Listen("tcp", addr)
+	}
+}
+
+// UnixListener returns a ListenerFunc which listens on a unix domain socket at path,
+// with the socket file permissions set to perm when it is non-zero. A stale socket file
+// left behind by a previous process is removed, but listening fails if another
+// process is still accepting connections on it.
+func UnixListener(path string, perm os.FileMode) ListenerFunc {
+	return func() (net.Listener, error) {
+		if err := removeStaleSocket(path); err != nil {
+			return nil, err
+		}
+
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+
+		if perm != 0 {
+			if err := os.Chmod(path, perm); err != nil {
+				_ = l.Close()
+
+				return nil, err
+			}
+		}
+
+		return l, nil
+	}
+}
+
+// Listener returns a ListenerFunc which returns the already open net.Listener
+func Listener(l net.Listener) ListenerFunc {
+	return func() (net.Listener, error) {
+		return l, nil
+	}
+}
+
+// NotifyAddr returns a ListenerFunc which calls fn with the address the
+// listener is bound to, useful when listening on port 0
+func NotifyAddr(nl ListenerFunc, fn func(net.Addr)) ListenerFunc {
+	return func() (net.Listener, error) {
+		l, err := nl()
+		if err != nil {
+			return nil, err
+		}
+
+		fn(l.Addr())
+
+		return l, nil
+	}
+}
+
+func removeStaleSocket(path string) error {
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a unix socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+
+	return os.Remove(path)
+}
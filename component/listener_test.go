@@ -0,0 +1,74 @@
+package component
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTCPListener(t *testing.T) {
+	var addr net.Addr
+
+	l, err := NotifyAddr(TCPListener("localhost:0"), func(a net.Addr) { addr = a })()
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.Equal(t, l.Addr(), addr)
+	assert.NotZero(t, addr.(*net.TCPAddr).Port)
+
+	_, err = NotifyAddr(TCPListener(":-1"), func(a net.Addr) { t.Fail() })()
+	assert.Error(t, err)
+}
+
+func TestUnixListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.sock")
+
+	l, err := UnixListener(path, 0o660)()
+	require.NoError(t, err)
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), fi.Mode().Perm())
+
+	_, err = UnixListener(path, 0)()
+	assert.EqualError(t, err, path+" is in use by another process")
+
+	require.NoError(t, l.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUnixListenerRemovesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.sock")
+
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	l, err := UnixListener(path, 0)()
+	require.NoError(t, err)
+	assert.NoError(t, l.Close())
+}
+
+func TestUnixListenerNotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.sock")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	_, err := UnixListener(path, 0)()
+	assert.EqualError(t, err, path+" exists and is not a unix socket")
+}
+
+func TestListener(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	got, err := Listener(l)()
+	assert.NoError(t, err)
+	assert.Equal(t, l, got)
+}
@@ -0,0 +1,104 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// ErrAlreadyRunning is returned by PIDFile when another live instance holds the file
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// PIDFile is a helper which returns an xrun.ComponentFunc guarding c to a single instance.
+// It locks the file at path and writes the current PID to it before running c, failing
+// with ErrAlreadyRunning when another process holds it. A file left behind by a process
+// which died is stale as its lock is released by the OS, it's taken over.
+// The file is removed once c returns, so c is usually the xrun.Manager of the whole service.
+func PIDFile(path string, c xrun.Component) xrun.ComponentFunc {
+	return func(ctx context.Context) error {
+		f, err := lockPIDFile(path)
+		if err != nil {
+			return err
+		}
+
+		err = c.Run(ctx)
+
+		// remove before unlocking so that a new instance never loses its file
+		return errors.Join(err, os.Remove(path), unlock(f), f.Close())
+	}
+}
+
+func lockPIDFile(path string) (*os.File, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		locked, err := tryLock(f)
+		if err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		if !locked {
+			pid := readPID(f)
+			_ = f.Close()
+
+			return nil, fmt.Errorf("pid file %s: %w with pid %d", path, ErrAlreadyRunning, pid)
+		}
+
+		// the holder may have removed the file between open and lock, retry on the new one
+		if !samePIDFile(path, f) {
+			_ = unlock(f)
+			_ = f.Close()
+
+			continue
+		}
+
+		if err := writePID(f); err != nil {
+			return nil, errors.Join(err, os.Remove(path), unlock(f), f.Close())
+		}
+
+		return f, nil
+	}
+}
+
+func samePIDFile(path string, f *os.File) bool {
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(pathInfo, fileInfo)
+}
+
+func readPID(f *os.File) int {
+	b := make([]byte, 32)
+
+	n, _ := f.ReadAt(b, 0)
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(b[:n])))
+
+	return pid
+}
+
+func writePID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+
+	return err
+}
@@ -0,0 +1,81 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gojekfarm/xrun"
+)
+
+func TestPIDFile(t *testing.T) {
+	testcases := []struct {
+		name    string
+		stale   bool
+		runErr  error
+		wantErr string
+	}{
+		{
+			name: "WritesPIDWhileRunning",
+		},
+		{
+			name:  "TakesOverStaleFile",
+			stale: true,
+		},
+		{
+			name:    "RemovesFileOnError",
+			runErr:  errors.New("run failed"),
+			wantErr: "run failed",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.pid")
+
+			if tc.stale {
+				assert.NoError(t, os.WriteFile(path, []byte("999999\n"), 0o644))
+			}
+
+			err := PIDFile(path, xrun.ComponentFunc(func(ctx context.Context) error {
+				b, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(b))
+
+				return tc.runErr
+			})).Run(context.Background())
+
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = os.Stat(path)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestPIDFileAlreadyRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.pid")
+
+	err := PIDFile(path, xrun.ComponentFunc(func(ctx context.Context) error {
+		return PIDFile(path, xrun.ComponentFunc(func(ctx context.Context) error {
+			t.Error("second instance must not run")
+
+			return nil
+		})).Run(ctx)
+	})).Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrAlreadyRunning)
+	assert.ErrorContains(t, err, "with pid "+strconv.Itoa(os.Getpid()))
+
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
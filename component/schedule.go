@@ -0,0 +1,275 @@
+package component
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes when a Cron component runs
+type Schedule interface {
+	// Next returns the next activation time, later than the given time
+	Next(time.Time) time.Time
+}
+
+// Every returns a Schedule which activates at a fixed interval,
+// it panics if d is not positive like time.NewTicker
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("cron: non-positive interval for Every")
+	}
+
+	return interval(d)
+}
+
+type interval time.Duration
+
+func (i interval) Next(t time.Time) time.Time { return t.Add(time.Duration(i)) }
+
+// ParseCron parses a standard cron expression with five fields:
+// minute, hour, day of month, month and day of week. Each field supports
+// '*', ranges (1-5), steps (*/15, 1-30/5), lists (1,15) and names (JAN, MON).
+// It also accepts the descriptors @yearly, @annually, @monthly, @weekly,
+// @daily, @midnight, @hourly and @every <duration>.
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@") {
+		return parseDescriptor(expr)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, found %d in %q", len(fields), expr)
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+
+	if s.dom, err = parseField(fields[2], daysOfMonth); err != nil {
+		return nil, err
+	}
+
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+
+	if s.dow, err = parseField(fields[4], daysOfWeek); err != nil {
+		return nil, err
+	}
+
+	// 7 is an alias of Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	// like in standard cron, a field starting with * such as */2 doesn't restrict the day
+	s.domAny = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	s.dowAny = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return s, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression cannot be parsed
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func parseDescriptor(expr string) (Schedule, error) {
+	switch expr {
+	case "@yearly", "@annually":
+		return ParseCron("0 0 1 1 *")
+	case "@monthly":
+		return ParseCron("0 0 1 * *")
+	case "@weekly":
+		return ParseCron("0 0 * * 0")
+	case "@daily", "@midnight":
+		return ParseCron("0 0 * * *")
+	case "@hourly":
+		return ParseCron("0 * * * *")
+	}
+
+	if d, ok := strings.CutPrefix(expr, "@every "); ok {
+		dur, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return nil, fmt.Errorf("cron: invalid duration in %q: %w", expr, err)
+		}
+
+		if dur <= 0 {
+			return nil, fmt.Errorf("cron: duration must be positive in %q", expr)
+		}
+
+		return Every(dur), nil
+	}
+
+	return nil, fmt.Errorf("cron: unknown descriptor %q", expr)
+}
+
+type bounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes     = bounds{name: "minute", min: 0, max: 59}
+	hours       = bounds{name: "hour", min: 0, max: 23}
+	daysOfMonth = bounds{name: "day of month", min: 1, max: 31}
+	months      = bounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	daysOfWeek = bounds{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		v, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+
+		set |= v
+	}
+
+	return set, nil
+}
+
+func parsePart(part string, b bounds) (uint64, error) {
+	rng, stepStr, hasStep := strings.Cut(part, "/")
+
+	step := 1
+
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+			return 0, fmt.Errorf("cron: invalid step %q in %s field", stepStr, b.name)
+		}
+	}
+
+	start, end := b.min, b.max
+
+	if rng != "*" && rng != "?" {
+		lo, hi, isRange := strings.Cut(rng, "-")
+
+		var err error
+		if start, err = parseValue(lo, b); err != nil {
+			return 0, err
+		}
+
+		end = start
+
+		if isRange {
+			if end, err = parseValue(hi, b); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			end = b.max
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("cron: invalid range %q in %s field", rng, b.name)
+		}
+	}
+
+	var set uint64
+	for v := start; v <= end; v += step {
+		set |= 1 << v
+	}
+
+	return set, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, fmt.Errorf("cron: invalid value %q in %s field, must be in [%d, %d]", s, b.name, b.min, b.max)
+	}
+
+	return v, nil
+}
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// maxSearch bounds the search for expressions which never match, example: 30th of February
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Duration(nextBit(s.minute, t.Minute())-t.Minute()) * time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows the cron convention where a day matches either field
+// when both day of month and day of week are restricted
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// nextBit returns the next set bit after v, or 60 when there is none
+// so that the minute rolls over to the next hour
+func nextBit(set uint64, v int) int {
+	rest := set >> uint(v+1)
+	if rest == 0 {
+		return 60
+	}
+
+	return v + 1 + bits.TrailingZeros64(rest)
+}
@@ -0,0 +1,79 @@
+package component
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2024, time.January, 31, 10, 15, 30, 0, time.UTC)
+
+	testcases := []struct {
+		name    string
+		expr    string
+		want    time.Time
+		wantErr string
+	}{
+		{name: "EveryMinute", expr: "* * * * *", want: time.Date(2024, time.January, 31, 10, 16, 0, 0, time.UTC)},
+		{name: "Step", expr: "*/20 * * * *", want: time.Date(2024, time.January, 31, 10, 20, 0, 0, time.UTC)},
+		{name: "MinuteRollsOverHour", expr: "5 * * * *", want: time.Date(2024, time.January, 31, 11, 5, 0, 0, time.UTC)},
+		{name: "List", expr: "0 9,17 * * *", want: time.Date(2024, time.January, 31, 17, 0, 0, 0, time.UTC)},
+		{name: "RangeWithStep", expr: "0 0-6/3 * * *", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "MonthName", expr: "0 0 1 MAR *", want: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "LeapDay", expr: "0 0 29 2 *", want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "DayOfWeekName", expr: "30 8 * * mon", want: time.Date(2024, time.February, 5, 8, 30, 0, 0, time.UTC)},
+		{name: "SundayAsSeven", expr: "0 0 * * 7", want: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "DayOfMonthOrDayOfWeek", expr: "0 0 15 * fri", want: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "DayOfMonthStepAndDayOfWeek", expr: "0 0 */2 * MON", want: time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "Hourly", expr: "@hourly", want: time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{name: "Daily", expr: "@daily", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Weekly", expr: "@weekly", want: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "Monthly", expr: "@monthly", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Yearly", expr: "@yearly", want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Every", expr: "@every 90s", want: base.Add(90 * time.Second)},
+		{name: "NeverMatches", expr: "0 0 30 2 *", want: time.Time{}},
+		{name: "TooFewFields", expr: "* * * *", wantErr: `cron: expected 5 fields, found 4 in "* * * *"`},
+		{name: "OutOfRange", expr: "60 * * * *", wantErr: `cron: invalid value "60" in minute field, must be in [0, 59]`},
+		{name: "InvalidStep", expr: "*/0 * * * *", wantErr: `cron: invalid step "0" in minute field`},
+		{name: "InvalidRange", expr: "* 5-1 * * *", wantErr: `cron: invalid range "5-1" in hour field`},
+		{name: "UnknownDescriptor", expr: "@sometimes", wantErr: `cron: unknown descriptor "@sometimes"`},
+		{name: "InvalidEvery", expr: "@every -1s", wantErr: `cron: duration must be positive in "@every -1s"`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := ParseCron(tc.expr)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, s.Next(base))
+		})
+	}
+}
+
+func TestCronScheduleLocation(t *testing.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	s := MustParseCron("0 9 * * *")
+
+	assert.Equal(t,
+		time.Date(2024, time.January, 2, 9, 0, 0, 0, loc),
+		s.Next(time.Date(2024, time.January, 1, 9, 30, 0, 0, loc)),
+	)
+}
+
+func TestMustParseCron(t *testing.T) {
+	assert.Panics(t, func() { MustParseCron("invalid") })
+}
+
+func TestEvery(t *testing.T) {
+	base := time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)
+
+	assert.Equal(t, base.Add(time.Minute), Every(time.Minute).Next(base))
+	assert.Panics(t, func() { Every(0) })
+	assert.Panics(t, func() { Every(-time.Second) })
+}
@@ -0,0 +1,88 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// TickerOptions holds options for Ticker
+type TickerOptions struct {
+	// Immediate runs the function as soon as the component starts
+	// instead of waiting for the first interval
+	Immediate bool
+	// MaxBackoff enables exponential backoff when the function returns errors,
+	// the wait doubles on every consecutive error up to MaxBackoff and
+	// resets to the interval on success
+	MaxBackoff time.Duration
+	// MaxConsecutiveErrors makes the component return the Nth consecutive error,
+	// zero means errors are never returned
+	MaxConsecutiveErrors int
+	// OnError is called with the errors returned by the function
+	OnError func(error)
+}
+
+// Ticker is a helper which returns an xrun.ComponentFunc to run fn periodically,
+// waiting for interval between the end of a run and the start of the next one
+func Ticker(interval time.Duration, fn func(ctx context.Context) error, opts TickerOptions) xrun.ComponentFunc {
+	return func(ctx context.Context) error {
+		wait := interval
+		failures := 0
+
+		if opts.Immediate {
+			wait = 0
+		}
+
+		xrun.Ready(ctx)
+
+		for {
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return nil
+			case <-timer.C:
+			}
+
+			err := fn(ctx)
+			if err == nil || ctx.Err() != nil {
+				wait, failures = interval, 0
+
+				continue
+			}
+
+			failures++
+
+			if opts.OnError != nil {
+				opts.OnError(err)
+			}
+
+			if opts.MaxConsecutiveErrors > 0 && failures >= opts.MaxConsecutiveErrors {
+				return fmt.Errorf("ticker: %d consecutive errors: %w", failures, err)
+			}
+
+			wait = backoff(interval, opts.MaxBackoff, failures)
+		}
+	}
+}
+
+func backoff(interval, maxBackoff time.Duration, failures int) time.Duration {
+	if maxBackoff <= interval {
+		return interval
+	}
+
+	d := interval
+	for i := 0; i < failures && d < maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > maxBackoff {
+		return maxBackoff
+	}
+
+	return d
+}
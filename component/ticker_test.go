@@ -0,0 +1,127 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TickerSuite struct {
+	suite.Suite
+}
+
+func TestTickerSuite(t *testing.T) {
+	suite.Run(t, new(TickerSuite))
+}
+
+func (s *TickerSuite) TestTicker() {
+	testcases := []struct {
+		name     string
+		interval time.Duration
+		opts     TickerOptions
+		results  []error
+		runFor   time.Duration
+		wantErr  string
+		wantRuns int
+	}{
+		{
+			name:     "RunsPeriodically",
+			interval: 40 * time.Millisecond,
+			runFor:   100 * time.Millisecond,
+			wantRuns: 2,
+		},
+		{
+			name:     "Immediate",
+			interval: 40 * time.Millisecond,
+			opts:     TickerOptions{Immediate: true},
+			runFor:   100 * time.Millisecond,
+			wantRuns: 3,
+		},
+		{
+			name:     "BackoffOnError",
+			interval: 20 * time.Millisecond,
+			opts:     TickerOptions{MaxBackoff: time.Second},
+			results:  []error{errors.New("e1"), errors.New("e2"), errors.New("e3")},
+			runFor:   250 * time.Millisecond,
+			wantRuns: 3,
+		},
+		{
+			name:     "ReturnsNthConsecutiveError",
+			interval: 10 * time.Millisecond,
+			opts:     TickerOptions{MaxConsecutiveErrors: 3},
+			results:  []error{errors.New("e1"), nil, errors.New("e2"), errors.New("e3"), errors.New("e4")},
+			runFor:   time.Second,
+			wantErr:  "ticker: 3 consecutive errors: e4",
+			wantRuns: 5,
+		},
+	}
+
+	for _, t := range testcases {
+		s.Run(t.name, func() {
+			var (
+				mu     sync.Mutex
+				runs   int
+				errs   []error
+				starts = time.Now()
+			)
+
+			c := Ticker(t.interval, func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				runs++
+				if runs <= len(t.results) {
+					return t.results[runs-1]
+				}
+				return nil
+			}, TickerOptions{
+				Immediate:            t.opts.Immediate,
+				MaxBackoff:           t.opts.MaxBackoff,
+				MaxConsecutiveErrors: t.opts.MaxConsecutiveErrors,
+				OnError: func(err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					errs = append(errs, err)
+				},
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), t.runFor)
+			defer cancel()
+
+			err := c.Run(ctx)
+			if t.wantErr != "" {
+				s.EqualError(err, t.wantErr)
+				s.Less(time.Since(starts), t.runFor)
+			} else {
+				s.NoError(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			s.Equal(t.wantRuns, runs)
+			s.Len(errs, countErrors(t.results, runs))
+		})
+	}
+}
+
+func countErrors(results []error, runs int) int {
+	n := 0
+	for i, err := range results {
+		if i < runs && err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, 0, 3))
+	assert.Equal(t, 2*time.Second, backoff(time.Second, time.Minute, 1))
+	assert.Equal(t, 8*time.Second, backoff(time.Second, time.Minute, 3))
+	assert.Equal(t, time.Minute, backoff(time.Second, time.Minute, 10))
+}
@@ -0,0 +1,98 @@
+package component
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader serves the key pair from certFile and keyFile,
+// reloading it whenever either of the files changes
+type certReloader struct {
+	certFile, keyFile string
+	onReload          func(error)
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newCertReloader(certFile, keyFile string, onReload func(error)) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, onReload: onReload}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, _ := r.fileVersion()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		v, err := r.fileVersion()
+		if err == nil && v == last {
+			continue
+		}
+
+		last = v
+
+		if err == nil {
+			err = r.reload()
+		}
+
+		if r.onReload != nil {
+			r.onReload(err)
+		}
+	}
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+
+	return nil
+}
+
+// fileVersion identifies the contents of the files by their size and modification time
+func (r *certReloader) fileVersion() (string, error) {
+	var v string
+
+	for _, f := range []string{r.certFile, r.keyFile} {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+
+		v += fmt.Sprintf("%s:%d:%d;", f, fi.Size(), fi.ModTime().UnixNano())
+	}
+
+	return v, nil
+}
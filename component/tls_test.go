@@ -0,0 +1,100 @@
+package component
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, 1)
+
+	reloaded := make(chan error, 1)
+	r, err := newCertReloader(certFile, keyFile, func(err error) { reloaded <- err })
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), serialOf(t, r))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go r.watch(ctx, 10*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	writeKeyPair(t, dir, 2)
+
+	assert.NoError(t, <-reloaded)
+	assert.Equal(t, int64(2), serialOf(t, r))
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+
+	assert.Error(t, <-reloaded)
+	assert.Equal(t, int64(2), serialOf(t, r), "previous certificate must be served when reload fails")
+}
+
+func TestNewCertReloaderError(t *testing.T) {
+	_, err := newCertReloader("missing.crt", "missing.key", nil)
+	assert.Error(t, err)
+}
+
+func serialOf(t *testing.T, r *certReloader) int64 {
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	return leaf.SerialNumber.Int64()
+}
+
+// writeKeyPair writes a self-signed certificate for localhost with the given serial number
+func writeKeyPair(t *testing.T, dir string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+// peerSerial connects to addr over TLS and returns the serial number of the served certificate
+func peerSerial(addr string) (int64, error) {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
@@ -0,0 +1,322 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// ErrSourceClosed is returned by a Source when there are no more items
+var ErrSourceClosed = errors.New("source closed")
+
+// Source provides items to a WorkerPool
+type Source[T any] interface {
+	// Next blocks until an item is available or the context is closed.
+	// Once exhausted, it must keep returning ErrSourceClosed.
+	Next(ctx context.Context) (T, error)
+}
+
+// ChannelSource returns a Source which receives items from ch,
+// it is exhausted once ch is closed
+func ChannelSource[T any](ch <-chan T) Source[T] {
+	return channelSource[T](ch)
+}
+
+type channelSource[T any] <-chan T
+
+func (c channelSource[T]) Next(ctx context.Context) (T, error) {
+	var zero T
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case item, ok := <-c:
+		if !ok {
+			return zero, ErrSourceClosed
+		}
+
+		return item, nil
+	}
+}
+
+// WorkerPoolOptions holds options for WorkerPool
+type WorkerPoolOptions[T any] struct {
+	// Source provides the items to process
+	Source Source[T]
+	// Handler processes a single item
+	Handler func(ctx context.Context, item T) error
+	// Workers is the number of goroutines pulling from Source, defaults to 1
+	Workers int
+	// DrainTimeout is the grace period for in-flight items on shutdown,
+	// zero waits indefinitely
+	DrainTimeout time.Duration
+	// OnError is called when Handler returns an error or panics
+	OnError func(item T, err error)
+}
+
+// WorkerPoolStats holds counters of a WorkerPool
+type WorkerPoolStats struct {
+	// Processed is the number of items handled, including the failed ones
+	Processed int64
+	// Failed is the number of items for which Handler returned an error or panicked
+	Failed int64
+	// Panicked is the number of items for which Handler panicked
+	Panicked int64
+	// Dropped is the number of in-flight items abandoned after DrainTimeout
+	Dropped int64
+}
+
+// WorkerPool is a component which runs workers pulling items from a Source.
+// On shutdown, it stops pulling and waits for in-flight items within DrainTimeout,
+// the ones still being processed after that are reported as dropped.
+type WorkerPool[T any] struct {
+	opts WorkerPoolOptions[T]
+
+	mu         sync.Mutex
+	size       int
+	live       int
+	running    bool
+	pullCtx    context.Context
+	handlerCtx context.Context
+	workers    []*poolWorker
+	wg         sync.WaitGroup
+	errCh      chan error
+
+	inFlight  atomic.Int64
+	processed atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewWorkerPool creates a WorkerPool with the provided WorkerPoolOptions
+func NewWorkerPool[T any](opts WorkerPoolOptions[T]) *WorkerPool[T] {
+	return &WorkerPool[T]{opts: opts, size: max1(opts.Workers)}
+}
+
+// Run starts the workers and blocks until the context is closed,
+// the Source is exhausted or it returns an error
+func (p *WorkerPool[T]) Run(ctx context.Context) error {
+	if p.opts.Source == nil || p.opts.Handler == nil {
+		return errors.New("worker pool: both Source and Handler are required")
+	}
+
+	pullCtx, stopPulling := context.WithCancel(ctx)
+	defer stopPulling()
+
+	// handlers keep the values of ctx, such as xrun.Logger, but outlive it while draining
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	p.mu.Lock()
+	p.running = true
+	p.pullCtx, p.handlerCtx = pullCtx, handlerCtx
+	p.workers = nil
+	p.errCh = make(chan error, 1)
+
+	for i := 0; i < p.size; i++ {
+		p.startWorker()
+	}
+	p.mu.Unlock()
+
+	xrun.Ready(ctx)
+
+	defer func() {
+		p.mu.Lock()
+		p.running = false
+		p.mu.Unlock()
+	}()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+		select {
+		case err = <-p.errCh:
+		default:
+		}
+
+		return err
+	case <-ctx.Done():
+	case err = <-p.errCh:
+	}
+
+	stopPulling()
+
+	return errors.Join(err, p.drain(done, cancelHandlers))
+}
+
+func (p *WorkerPool[T]) drain(done <-chan struct{}, cancelHandlers context.CancelFunc) error {
+	var timeout <-chan time.Time
+
+	if p.opts.DrainTimeout > 0 {
+		timer := time.NewTimer(p.opts.DrainTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-timeout:
+	}
+
+	cancelHandlers()
+
+	dropped := p.inFlight.Load()
+	p.dropped.Add(dropped)
+
+	return fmt.Errorf("worker pool: dropped %d in-flight items after drain timeout(%s)", dropped, p.opts.DrainTimeout)
+}
+
+// Resize changes the number of workers, n is at least 1.
+// Workers which are removed finish their in-flight item before exiting.
+func (p *WorkerPool[T]) Resize(n int) {
+	n = max1(n)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// once all workers have exited, the pool is done and no more workers are started
+	if !p.running || p.live == 0 {
+		p.size = n
+
+		return
+	}
+
+	for len(p.workers) < n {
+		p.startWorker()
+	}
+
+	for len(p.workers) > n {
+		last := len(p.workers) - 1
+		p.workers[last].cancel()
+		p.workers = p.workers[:last]
+	}
+
+	p.size = n
+}
+
+// Size returns the number of workers
+func (p *WorkerPool[T]) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.size
+}
+
+// Stats returns the counters of processed, failed, panicked and dropped items
+func (p *WorkerPool[T]) Stats() WorkerPoolStats {
+	return WorkerPoolStats{
+		Processed: p.processed.Load(),
+		Failed:    p.failed.Load(),
+		Panicked:  p.panicked.Load(),
+		Dropped:   p.dropped.Load(),
+	}
+}
+
+// poolWorker is a running worker, it's removed from the pool once it exits
+type poolWorker struct {
+	cancel context.CancelFunc
+}
+
+// startWorker must be called with p.mu held
+func (p *WorkerPool[T]) startWorker() {
+	ctx, cancel := context.WithCancel(p.pullCtx)
+	w := &poolWorker{cancel: cancel}
+	p.workers = append(p.workers, w)
+	p.live++
+
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			cancel()
+
+			p.mu.Lock()
+			p.removeWorker(w)
+			p.live--
+			p.mu.Unlock()
+		}()
+
+		for {
+			item, err := p.opts.Source.Next(ctx)
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, ErrSourceClosed) {
+					select {
+					case p.errCh <- err:
+					default:
+					}
+				}
+
+				return
+			}
+
+			p.handle(item)
+		}
+	}()
+}
+
+// removeWorker must be called with p.mu held
+func (p *WorkerPool[T]) removeWorker(w *poolWorker) {
+	for i, pw := range p.workers {
+		if pw == w {
+			p.workers = append(p.workers[:i], p.workers[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (p *WorkerPool[T]) handle(item T) {
+	p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	err := p.call(item)
+
+	p.processed.Add(1)
+
+	if err == nil {
+		return
+	}
+
+	p.failed.Add(1)
+
+	if p.opts.OnError != nil {
+		p.opts.OnError(item, err)
+	}
+}
+
+func (p *WorkerPool[T]) call(item T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.panicked.Add(1)
+
+			err = fmt.Errorf("worker pool: handler panicked: %v", r)
+		}
+	}()
+
+	return p.opts.Handler(p.handlerCtx, item)
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
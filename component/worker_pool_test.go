@@ -0,0 +1,257 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/gojekfarm/xrun"
+)
+
+type WorkerPoolSuite struct {
+	suite.Suite
+}
+
+func TestWorkerPoolSuite(t *testing.T) {
+	suite.Run(t, new(WorkerPoolSuite))
+}
+
+func (s *WorkerPoolSuite) TestProcessesUntilSourceIsClosed() {
+	ch := make(chan int, 10)
+	for i := 1; i <= 10; i++ {
+		ch <- i
+	}
+	close(ch)
+
+	var sum atomic.Int64
+	var failed []int
+	var mu sync.Mutex
+
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source:  ChannelSource(ch),
+		Workers: 3,
+		Handler: func(ctx context.Context, item int) error {
+			switch item {
+			case 3:
+				return errors.New("cannot process 3")
+			case 7:
+				panic("cannot process 7")
+			}
+			sum.Add(int64(item))
+			return nil
+		},
+		OnError: func(item int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, item)
+		},
+	})
+
+	s.NoError(p.Run(context.Background()))
+	s.Equal(int64(45), sum.Load())
+	s.ElementsMatch([]int{3, 7}, failed)
+	s.Equal(WorkerPoolStats{Processed: 10, Failed: 2, Panicked: 1}, p.Stats())
+}
+
+func (s *WorkerPoolSuite) TestDrainsInFlightItemsOnShutdown() {
+	ch := make(chan int)
+	started := make(chan struct{})
+
+	var done atomic.Bool
+
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source: ChannelSource(ch),
+		Handler: func(ctx context.Context, item int) error {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			done.Store(true)
+			return ctx.Err()
+		},
+		DrainTimeout: time.Second,
+	})
+
+	m := xrun.NewManager()
+	s.NoError(m.Add(p))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	ch <- 1
+	<-started
+	cancel()
+
+	s.NoError(<-errCh)
+	s.True(done.Load())
+	s.Equal(WorkerPoolStats{Processed: 1}, p.Stats())
+}
+
+func (s *WorkerPoolSuite) TestReportsDroppedItemsAfterDrainTimeout() {
+	ch := make(chan int)
+	started := make(chan struct{}, 2)
+
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source:  ChannelSource(ch),
+		Workers: 2,
+		Handler: func(ctx context.Context, item int) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		DrainTimeout: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- p.Run(ctx) }()
+
+	ch <- 1
+	ch <- 2
+	<-started
+	<-started
+	cancel()
+
+	s.EqualError(<-errCh, "worker pool: dropped 2 in-flight items after drain timeout(50ms)")
+	s.Equal(int64(2), p.Stats().Dropped)
+}
+
+func (s *WorkerPoolSuite) TestSourceError() {
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source:  failingSource{},
+		Handler: func(ctx context.Context, item int) error { return nil },
+	})
+
+	s.EqualError(p.Run(context.Background()), "source failure")
+}
+
+func (s *WorkerPoolSuite) TestMissingOptions() {
+	s.Error(NewWorkerPool(WorkerPoolOptions[int]{}).Run(context.Background()))
+}
+
+func (s *WorkerPoolSuite) TestResize() {
+	ch := make(chan int)
+
+	var running, maxRunning atomic.Int32
+	release := make(chan struct{})
+
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source: ChannelSource(ch),
+		Handler: func(ctx context.Context, item int) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-release
+			return nil
+		},
+	})
+	s.Equal(1, p.Size())
+
+	p.Resize(0)
+	s.Equal(1, p.Size())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- p.Run(ctx) }()
+
+	s.Eventually(func() bool {
+		p.Resize(4)
+		return p.Size() == 4
+	}, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		ch <- i
+	}
+	s.Eventually(func() bool { return running.Load() == 4 }, time.Second, 10*time.Millisecond)
+
+	p.Resize(2)
+	s.Equal(2, p.Size())
+	close(release)
+
+	s.Eventually(func() bool { return running.Load() == 0 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	s.NoError(<-errCh)
+	s.Equal(int32(4), maxRunning.Load())
+	s.Equal(int64(4), p.Stats().Processed)
+}
+
+func (s *WorkerPoolSuite) TestResizeAfterWorkerExited() {
+	var calls atomic.Int32
+
+	p := NewWorkerPool(WorkerPoolOptions[int]{
+		Source: sourceFunc[int](func(ctx context.Context) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, ErrSourceClosed
+			}
+
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		}),
+		Workers: 3,
+		Handler: func(ctx context.Context, item int) error { return nil },
+	})
+
+	live := func() int {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return p.live
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- p.Run(ctx) }()
+
+	s.Eventually(func() bool { return live() == 2 }, time.Second, 10*time.Millisecond)
+
+	p.Resize(2)
+	s.Never(func() bool { return live() != 2 }, 50*time.Millisecond, 10*time.Millisecond)
+
+	p.Resize(1)
+	s.Eventually(func() bool { return live() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	s.NoError(<-errCh)
+}
+
+func (s *WorkerPoolSuite) TestHandlerContextValues() {
+	ch := make(chan int, 1)
+	ch <- 1
+	close(ch)
+
+	var name string
+
+	m := xrun.NewManager(xrun.ExitWhenAllDone(true))
+	s.NoError(m.Add(xrun.Named("pool", NewWorkerPool(WorkerPoolOptions[int]{
+		Source: ChannelSource(ch),
+		Handler: func(ctx context.Context, item int) error {
+			name = xrun.ComponentName(ctx)
+
+			return nil
+		},
+	}))))
+	s.NoError(m.Run(context.Background()))
+
+	s.Equal("pool", name)
+}
+
+type sourceFunc[T any] func(ctx context.Context) (T, error)
+
+func (f sourceFunc[T]) Next(ctx context.Context) (T, error) { return f(ctx) }
+
+type failingSource struct{}
+
+func (failingSource) Next(ctx context.Context) (int, error) {
+	return 0, errors.New("source failure")
+}
@@ -1,6 +1,10 @@
 package grpc_test
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"google.golang.org/grpc"
 
 	xgrpc "github.com/gojekfarm/xrun/component/x/grpc"
@@ -12,3 +16,18 @@ func ExampleServer() {
 		NewListener: xgrpc.NewListener(":8500"),
 	})
 }
+
+func ExampleServer_health() {
+	var db *sql.DB
+
+	xgrpc.Server(xgrpc.Options{
+		Server:      grpc.NewServer(),
+		NewListener: xgrpc.NewListener(":8500"),
+		Health: &xgrpc.HealthOptions{
+			Checks: map[string]func(ctx context.Context) error{
+				"orders.v1.OrderService": db.PingContext,
+			},
+			CheckInterval: 5 * time.Second,
+		},
+	})
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/gojekfarm/xrun"
 )
 
+const defaultCheckInterval = 10 * time.Second
+
 // Options holds options for Server
 type Options struct {
 	Server      *grpc.Server
@@ -17,6 +22,21 @@ type Options struct {
 	PreStart    func()
 	PreStop     func()
 	PostStop    func()
+	// Health registers the grpc_health_v1 service on Server when set
+	Health *HealthOptions
+}
+
+// HealthOptions holds options for the grpc_health_v1 service registered by Server.
+// Once the listener is accepting connections and the checks have run once, the server
+// and its services are SERVING, except the ones whose check fails. They are all
+// NOT_SERVING as soon as the context is closed, before PreStop and GracefulStop,
+// so that health-checked clients drain first.
+type HealthOptions struct {
+	// Checks report the status of services by name, a service is NOT_SERVING while its
+	// check returns an error. The empty name reports the status of the whole server.
+	Checks map[string]func(ctx context.Context) error
+	// CheckInterval is the interval at which Checks run, defaults to 10 seconds
+	CheckInterval time.Duration
 }
 
 // Server is a helper which returns a xrun.ComponentFunc to start a grpc.Server
@@ -27,6 +47,12 @@ func Server(opts Options) xrun.ComponentFunc {
 	pst := opts.PreStop
 	pstp := opts.PostStop
 
+	var hs *healthService
+
+	if opts.Health != nil {
+		hs = newHealthService(srv, *opts.Health)
+	}
+
 	return func(ctx context.Context) error {
 		l, err := nl()
 		if err != nil {
@@ -45,12 +71,23 @@ func Server(opts Options) xrun.ComponentFunc {
 			}
 		}(errCh)
 
+		if hs != nil {
+			stopChecks := hs.serve(ctx)
+			defer stopChecks()
+		}
+
+		xrun.Ready(ctx)
+
 		select {
 		case <-ctx.Done():
 		case err := <-errCh:
 			return err
 		}
 
+		if hs != nil {
+			hs.shutdown()
+		}
+
 		if pst != nil {
 			pst()
 		}
@@ -70,3 +107,98 @@ func NewListener(address string) func() (net.Listener, error) {
 		return net.Listen("tcp", address)
 	}
 }
+
+type healthService struct {
+	srv    *grpc.Server
+	health *health.Server
+	opts   HealthOptions
+}
+
+// newHealthService registers the health service, which must be done before srv serves
+func newHealthService(srv *grpc.Server, opts HealthOptions) *healthService {
+	hs := &healthService{srv: srv, health: health.NewServer(), opts: opts}
+
+	healthpb.RegisterHealthServer(srv, hs.health)
+
+	return hs
+}
+
+// serve runs the checks once, then sets the services without a check to SERVING
+// and keeps running the checks, it returns a function which waits for them to stop
+func (h *healthService) serve(ctx context.Context) func() {
+	statuses := h.check(ctx)
+
+	for _, name := range h.services() {
+		if _, ok := h.opts.Checks[name]; !ok {
+			statuses[name] = healthpb.HealthCheckResponse_SERVING
+		}
+	}
+
+	h.health.Resume()
+	h.update(statuses)
+
+	if len(h.opts.Checks) == 0 {
+		return func() {}
+	}
+
+	interval := h.opts.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.update(h.check(ctx))
+			}
+		}
+	}()
+
+	return func() { <-done }
+}
+
+// check runs the checks and returns the status of the services they report
+func (h *healthService) check(ctx context.Context) map[string]healthpb.HealthCheckResponse_ServingStatus {
+	statuses := make(map[string]healthpb.HealthCheckResponse_ServingStatus, len(h.opts.Checks))
+
+	for name, check := range h.opts.Checks {
+		statuses[name] = healthpb.HealthCheckResponse_SERVING
+		if err := check(ctx); err != nil {
+			statuses[name] = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	return statuses
+}
+
+func (h *healthService) update(statuses map[string]healthpb.HealthCheckResponse_ServingStatus) {
+	for name, status := range statuses {
+		h.health.SetServingStatus(name, status)
+	}
+}
+
+// shutdown sets all the services to NOT_SERVING and ignores updates until serve is called again
+func (h *healthService) shutdown() {
+	h.health.Shutdown()
+}
+
+// services returns the name of the registered services and the empty name for the whole server
+func (h *healthService) services() []string {
+	names := []string{""}
+
+	for name := range h.srv.GetServiceInfo() {
+		names = append(names, name)
+	}
+
+	return names
+}
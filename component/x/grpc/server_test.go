@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +13,8 @@ import (
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/net/nettest"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/gojekfarm/xrun"
 )
@@ -143,3 +146,139 @@ func TestNewListener(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, l)
 }
+
+func (s *ServerTestSuite) TestHealth() {
+	l, err := nettest.NewLocalListener("tcp")
+	s.Require().NoError(err)
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		s.Require().NoError(err)
+
+		return resp.GetStatus()
+	}
+
+	var (
+		mu       sync.Mutex
+		dbErr    = errors.New("db unavailable")
+		onStop   healthpb.HealthCheckResponse_ServingStatus
+		stopping = make(chan struct{})
+	)
+
+	c := Server(Options{
+		Server:      grpc.NewServer(),
+		NewListener: func() (net.Listener, error) { return l, nil },
+		PreStop: func() {
+			onStop = status("")
+			close(stopping)
+		},
+		Health: &HealthOptions{
+			Checks: map[string]func(ctx context.Context) error{
+				"db": func(ctx context.Context) error {
+					mu.Lock()
+					defer mu.Unlock()
+
+					return dbErr
+				},
+			},
+			CheckInterval: 10 * time.Millisecond,
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- c.Run(ctx) }()
+
+	s.Eventually(func() bool {
+		return status("") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+	s.Equal(healthpb.HealthCheckResponse_SERVING, status(healthpb.Health_ServiceDesc.ServiceName))
+	s.Equal(healthpb.HealthCheckResponse_NOT_SERVING, status("db"))
+
+	mu.Lock()
+	dbErr = nil
+	mu.Unlock()
+
+	s.Eventually(func() bool {
+		return status("db") == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-stopping
+
+	s.Equal(healthpb.HealthCheckResponse_NOT_SERVING, onStop)
+	s.NoError(<-errCh)
+}
+
+func (s *ServerTestSuite) TestHealthBeforeFirstCheck() {
+	l, err := nettest.NewLocalListener("tcp")
+	s.Require().NoError(err)
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	// a registered service, which is reported by the server without a check
+	service := healthpb.Health_ServiceDesc.ServiceName
+
+	checking := make(chan struct{})
+	release := make(chan struct{})
+
+	var once sync.Once
+
+	c := Server(Options{
+		Server:      grpc.NewServer(),
+		NewListener: func() (net.Listener, error) { return l, nil },
+		Health: &HealthOptions{
+			Checks: map[string]func(ctx context.Context) error{
+				service: func(ctx context.Context) error {
+					once.Do(func() {
+						close(checking)
+						<-release
+					})
+
+					return errors.New("unavailable")
+				},
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- c.Run(ctx) }()
+
+	<-checking
+
+	checkCtx, checkCancel := context.WithTimeout(context.Background(), time.Second)
+	defer checkCancel()
+
+	resp, err := client.Check(checkCtx, &healthpb.HealthCheckRequest{Service: service})
+	if err == nil {
+		s.NotEqual(healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	close(release)
+
+	s.Eventually(func() bool {
+		resp, err := client.Check(checkCtx, &healthpb.HealthCheckRequest{Service: service})
+		return err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	s.NoError(<-errCh)
+}
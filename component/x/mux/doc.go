@@ -0,0 +1,2 @@
+// Package mux serves gRPC and HTTP on a single listener.
+package mux
@@ -0,0 +1,34 @@
+package mux_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"google.golang.org/grpc"
+
+	"github.com/gojekfarm/xrun/component"
+	xgrpc "github.com/gojekfarm/xrun/component/x/grpc"
+	"github.com/gojekfarm/xrun/component/x/mux"
+)
+
+func ExampleServer() {
+	c := mux.Server(mux.Options{
+		NewListener: component.TCPListener(":8080"),
+		GRPC: xgrpc.Options{
+			Server: grpc.NewServer(),
+			Health: &xgrpc.HealthOptions{},
+		},
+		HTTP: component.HTTPServerOptions{
+			Server: &http.Server{Handler: http.NotFoundHandler()},
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,202 @@
+package mux
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// subListener is a net.Listener which receives the connections routed to it
+type subListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+	err   error
+}
+
+func newSubListener(addr net.Addr) *subListener {
+	return &subListener{addr: addr, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *subListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+func (l *subListener) Close() error {
+	l.closeWithErr(net.ErrClosed)
+
+	return nil
+}
+
+func (l *subListener) Addr() net.Addr { return l.addr }
+
+func (l *subListener) closeWithErr(err error) {
+	l.once.Do(func() {
+		l.err = err
+		close(l.done)
+	})
+}
+
+// push hands c to the server accepting from l, c is closed if l is closed
+func (l *subListener) push(c net.Conn) {
+	select {
+	case l.conns <- c:
+	case <-l.done:
+		_ = c.Close()
+	}
+}
+
+// match reads the beginning of c to find whether it carries gRPC, it returns
+// a net.Conn which replays what was read
+func match(c net.Conn) (net.Conn, bool, error) {
+	var buf bytes.Buffer
+
+	isHTTP2, err := matchPreface(io.TeeReader(c, &buf))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !isHTTP2 {
+		return &replayConn{Conn: c, r: io.MultiReader(&buf, c)}, false, nil
+	}
+
+	// clients like grpc-go wait for the server settings before sending a request,
+	// the acknowledgement of these settings is not replayed to the server
+	if err := http2.NewFramer(c, nil).WriteSettings(); err != nil {
+		return nil, false, err
+	}
+
+	var frames bytes.Buffer
+
+	isGRPC, err := matchGRPC(io.TeeReader(c, &frames))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &replayConn{
+		Conn: c,
+		r:    io.MultiReader(&buf, &settingsAckFilter{r: io.MultiReader(&frames, c)}),
+	}, isGRPC, nil
+}
+
+// matchPreface reports whether r starts with the HTTP/2 client preface,
+// it reads no more than the preface
+func matchPreface(r io.Reader) (bool, error) {
+	preface := []byte(http2.ClientPreface)
+	b := make([]byte, len(preface))
+
+	for n := 0; n < len(preface); {
+		k, err := r.Read(b[n:])
+		n += k
+
+		if !bytes.Equal(b[:n], preface[:n]) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// matchGRPC reports whether the first request in the HTTP/2 frames
+// read from r has the application/grpc content-type
+func matchGRPC(r io.Reader) (bool, error) {
+	framer := http2.NewFramer(io.Discard, r)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+
+	for {
+		f, err := framer.ReadFrame()
+		if err != nil {
+			return false, err
+		}
+
+		h, ok := f.(*http2.MetaHeadersFrame)
+		if !ok {
+			continue
+		}
+
+		for _, hf := range h.RegularFields() {
+			if hf.Name == "content-type" {
+				return strings.HasPrefix(hf.Value, "application/grpc"), nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+const frameHeaderLen = 9
+
+// settingsAckFilter reads HTTP/2 frames from r and drops the first SETTINGS
+// acknowledgement, which is the one of the settings sent by match
+type settingsAckFilter struct {
+	r       io.Reader
+	dropped bool
+	header  []byte
+	payload int
+}
+
+func (f *settingsAckFilter) Read(p []byte) (int, error) {
+	for {
+		switch {
+		case len(f.header) > 0:
+			n := copy(p, f.header)
+			f.header = f.header[n:]
+
+			return n, nil
+		case f.payload > 0:
+			if len(p) > f.payload {
+				p = p[:f.payload]
+			}
+
+			n, err := f.r.Read(p)
+			f.payload -= n
+
+			return n, err
+		case f.dropped:
+			return f.r.Read(p)
+		}
+
+		header := make([]byte, frameHeaderLen)
+		if _, err := io.ReadFull(f.r, header); err != nil {
+			return 0, err
+		}
+
+		length := int(header[0])<<16 | int(header[1])<<8 | int(header[2])
+		ft, flags := http2.FrameType(header[3]), http2.Flags(header[4])
+
+		if ft == http2.FrameSettings && flags.Has(http2.FlagSettingsAck) {
+			f.dropped = true
+
+			if _, err := io.CopyN(io.Discard, f.r, int64(length)); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		f.header, f.payload = header, length
+	}
+}
+
+// replayConn is a net.Conn which replays the bytes read to route it
+type replayConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *replayConn) Read(p []byte) (int, error) { return c.r.Read(p) }
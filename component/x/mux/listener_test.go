@@ -0,0 +1,61 @@
+package mux
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+)
+
+func TestSettingsAckFilter(t *testing.T) {
+	var in, want bytes.Buffer
+
+	writeFrames := func(w io.Writer, acks int) {
+		fr := http2.NewFramer(w, nil)
+
+		assert.NoError(t, fr.WriteSettings(http2.Setting{ID: http2.SettingInitialWindowSize, Val: 1 << 20}))
+
+		for i := 0; i < acks; i++ {
+			assert.NoError(t, fr.WriteSettingsAck())
+		}
+
+		assert.NoError(t, fr.WriteWindowUpdate(0, 1<<16))
+		assert.NoError(t, fr.WritePing(false, [8]byte{1}))
+	}
+
+	// only the first acknowledgement is dropped
+	writeFrames(&in, 2)
+	writeFrames(&want, 1)
+
+	got, err := io.ReadAll(&settingsAckFilter{r: &in})
+	assert.NoError(t, err)
+	assert.Equal(t, want.Bytes(), got)
+}
+
+func TestMatchPreface(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "HTTP1", input: "GET / HTTP/1.1\r\n\r\n", want: false},
+		{name: "ShortHTTP1", input: "GET /", want: false},
+		{name: "HTTP2", input: http2.ClientPreface, want: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// a pipe blocks after the input, so the match must not read past a mismatch
+			r, w := io.Pipe()
+			defer r.Close()
+
+			go func() { _, _ = w.Write([]byte(tc.input)) }()
+
+			got, err := matchPreface(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
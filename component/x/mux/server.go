@@ -0,0 +1,210 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+	xgrpc "github.com/gojekfarm/xrun/component/x/grpc"
+)
+
+const defaultRouteTimeout = 10 * time.Second
+
+// Options holds options for Server
+type Options struct {
+	// NewListener creates the listener shared by both servers
+	NewListener func() (net.Listener, error)
+	// GRPC holds the options of the gRPC server, its NewListener is ignored
+	GRPC xgrpc.Options
+	// HTTP holds the options of the HTTP server, its NewListener and TLS options are ignored
+	HTTP component.HTTPServerOptions
+	// RouteTimeout limits the time to read the beginning of a connection
+	// to decide which server it's routed to, defaults to 10 seconds
+	RouteTimeout time.Duration
+}
+
+// Server is a helper which returns an xrun.ComponentFunc to serve gRPC and HTTP on a single
+// listener. HTTP/2 connections starting with a request whose content-type is application/grpc
+// are served by the gRPC server, all the other connections are served by the HTTP server.
+// HTTP/2 without TLS (h2c) is only spoken by the HTTP server when its handler supports it.
+// Both servers are started and shutdown with the semantics of xgrpc.Server and
+// component.HTTPServer, the component is ready once both servers are.
+func Server(opts Options) xrun.ComponentFunc {
+	var (
+		mu              sync.Mutex
+		grpcL, httpL    *subListener
+		routeTimeout    = opts.RouteTimeout
+		grpcOpts        = opts.GRPC
+		httpOpts        = opts.HTTP
+		currentListener = func(l **subListener) func() (net.Listener, error) {
+			return func() (net.Listener, error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				return *l, nil
+			}
+		}
+	)
+
+	if routeTimeout <= 0 {
+		routeTimeout = defaultRouteTimeout
+	}
+
+	grpcOpts.NewListener = currentListener(&grpcL)
+	httpOpts.NewListener = currentListener(&httpL)
+	httpOpts.CertFile, httpOpts.KeyFile, httpOpts.TLSConfig = "", "", nil
+
+	grpcServer := xgrpc.Server(grpcOpts)
+	httpServer := component.HTTPServer(httpOpts)
+
+	return func(ctx context.Context) error {
+		l, err := opts.NewListener()
+		if err != nil {
+			return err
+		}
+
+		r := &router{
+			l:            l,
+			grpc:         newSubListener(l.Addr()),
+			http:         newSubListener(l.Addr()),
+			routeTimeout: routeTimeout,
+			routing:      make(map[net.Conn]struct{}),
+		}
+
+		mu.Lock()
+		grpcL, httpL = r.grpc, r.http
+		mu.Unlock()
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			r.serve()
+		}()
+
+		err = xrun.All(xrun.NoTimeout, grpcServer, httpServer).Run(ctx)
+
+		return errors.Join(err, r.close(done))
+	}
+}
+
+type router struct {
+	l            net.Listener
+	grpc, http   *subListener
+	routeTimeout time.Duration
+	wg           sync.WaitGroup
+
+	mu      sync.Mutex
+	closed  bool
+	routing map[net.Conn]struct{}
+}
+
+// serve accepts connections and routes them until the listener is closed
+func (r *router) serve() {
+	defer r.wg.Wait()
+
+	for {
+		c, err := r.l.Accept()
+		if err != nil {
+			r.grpc.closeWithErr(err)
+			r.http.closeWithErr(err)
+
+			return
+		}
+
+		if !r.track(c) {
+			_ = c.Close()
+
+			continue
+		}
+
+		r.wg.Add(1)
+
+		go func() {
+			defer r.wg.Done()
+
+			rc, l := r.route(c)
+
+			// once untracked, c is owned by the server it's routed to
+			if !r.untrack(c) || rc == nil {
+				_ = c.Close()
+
+				return
+			}
+
+			l.push(rc)
+		}()
+	}
+}
+
+// route returns the connection to hand to the subListener it's routed to,
+// or a nil connection when it can't be routed
+func (r *router) route(c net.Conn) (net.Conn, *subListener) {
+	if err := c.SetDeadline(time.Now().Add(r.routeTimeout)); err != nil {
+		return nil, nil
+	}
+
+	rc, isGRPC, err := match(c)
+	if err != nil {
+		return nil, nil
+	}
+
+	if err := c.SetDeadline(time.Time{}); err != nil {
+		return nil, nil
+	}
+
+	if isGRPC {
+		return rc, r.grpc
+	}
+
+	return rc, r.http
+}
+
+// track records c as being routed, it reports false once the router is closed
+func (r *router) track(c net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false
+	}
+
+	r.routing[c] = struct{}{}
+
+	return true
+}
+
+// untrack removes c from the connections being routed, it reports false once the router is closed
+func (r *router) untrack(c net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.routing, c)
+
+	return !r.closed
+}
+
+// close stops accepting and closes the connections which are not routed yet
+func (r *router) close(done <-chan struct{}) error {
+	err := r.l.Close()
+
+	r.mu.Lock()
+	r.closed = true
+
+	for c := range r.routing {
+		_ = c.Close()
+	}
+	r.mu.Unlock()
+
+	<-done
+
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,152 @@
+package mux
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/net/nettest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+	xgrpc "github.com/gojekfarm/xrun/component/x/grpc"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) TestServer() {
+	l, err := nettest.NewLocalListener("tcp")
+	s.Require().NoError(err)
+
+	addr := l.Addr().String()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+
+	ready := make(chan struct{})
+
+	m := xrun.NewManager(xrun.Hooks{OnReady: func() { close(ready) }})
+	s.NoError(m.Add(Server(Options{
+		NewListener: func() (net.Listener, error) { return l, nil },
+		GRPC: xgrpc.Options{
+			Server: grpc.NewServer(),
+			Health: &xgrpc.HealthOptions{},
+		},
+		HTTP: component.HTTPServerOptions{
+			Server: &http.Server{Handler: h2c.NewHandler(handler, &http2.Server{})},
+		},
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- m.Run(ctx) }()
+
+	select {
+	case <-ready:
+	case <-time.After(time.Second):
+		s.FailNow("server is not ready")
+	}
+
+	s.Run("GRPC", func() {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		s.Require().NoError(err)
+
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		s.Require().NoError(err)
+		s.Equal(healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+
+	s.Run("HTTP1", func() {
+		resp, err := http.Get("http://" + addr)
+		s.Require().NoError(err)
+
+		defer resp.Body.Close()
+
+		b, _ := io.ReadAll(resp.Body)
+		s.Equal("HTTP/1.1", string(b))
+	})
+
+	s.Run("H2C", func() {
+		client := &http.Client{Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}}
+
+		resp, err := client.Get("http://" + addr)
+		s.Require().NoError(err)
+
+		defer resp.Body.Close()
+
+		b, _ := io.ReadAll(resp.Body)
+		s.Equal("HTTP/2.0", string(b))
+	})
+
+	cancel()
+	s.NoError(<-errCh)
+
+	_, err = net.Dial("tcp", addr)
+	s.Error(err)
+}
+
+func (s *ServerTestSuite) TestUnroutedConnectionsAreClosedOnStop() {
+	l, err := nettest.NewLocalListener("tcp")
+	s.Require().NoError(err)
+
+	c := Server(Options{
+		NewListener: func() (net.Listener, error) { return l, nil },
+		GRPC:        xgrpc.Options{Server: grpc.NewServer()},
+		HTTP:        component.HTTPServerOptions{Server: &http.Server{}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- c.Run(ctx) }()
+
+	// a connection which never sends anything can't be routed
+	conn, err := net.Dial("tcp", l.Addr().String())
+	s.Require().NoError(err)
+
+	defer conn.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+
+	cancel()
+	s.NoError(<-errCh)
+	s.Less(time.Since(start), time.Second)
+
+	_, err = conn.Read(make([]byte, 1))
+	s.ErrorIs(err, io.EOF)
+}
+
+func (s *ServerTestSuite) TestListenerError() {
+	c := Server(Options{
+		NewListener: func() (net.Listener, error) { return nil, net.ErrClosed },
+	})
+
+	s.ErrorIs(c.Run(context.Background()), net.ErrClosed)
+}
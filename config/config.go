@@ -0,0 +1,306 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// Config describes a tree of managers and components, see NewManager.
+//
+//	name: orders
+//	shutdownTimeout: 30s
+//	components:
+//	  - type: http
+//	    name: api
+//	    dependsOn: [migrations]
+//	    options:
+//	      addr: ":8080"
+//	  - type: migrations
+//	    primary: false
+//	  - name: workers
+//	    enabled: false
+//	    components:
+//	      - type: consumer
+type Config struct {
+	// Name is the name of the root Manager, see xrun.Name
+	Name            string        `yaml:"name"`
+	ShutdownTimeout xrun.Duration `yaml:"shutdownTimeout"`
+	StartupTimeout  xrun.Duration `yaml:"startupTimeout"`
+	Components      []Component   `yaml:"components"`
+}
+
+// Component describes an xrun.Component of a registered type,
+// or a nested Manager when Type is empty
+type Component struct {
+	// Type is the type the Component was registered with, see Register
+	Type string `yaml:"type"`
+	// Name is the name of the Component, see xrun.Named. It defaults to Type
+	// and must be unique among the components of a Manager.
+	Name string `yaml:"name"`
+	// Enabled toggles the Component, it defaults to true
+	Enabled *bool `yaml:"enabled"`
+	// Primary marks the Component as primary, see xrun.Primary
+	Primary bool `yaml:"primary"`
+	// DependsOn are the names of the components of the same Manager
+	// which must be ready before the Component is started
+	DependsOn []string `yaml:"dependsOn"`
+	// StartupTimeout is the startup timeout of the Component, see xrun.ReadyWithin
+	StartupTimeout xrun.Duration `yaml:"startupTimeout"`
+	// Options are decoded by the Factory of Type
+	Options yaml.Node `yaml:"options"`
+
+	// ShutdownTimeout is the shutdown timeout of a nested Manager
+	ShutdownTimeout xrun.Duration `yaml:"shutdownTimeout"`
+	// Components are the components of a nested Manager
+	Components []Component `yaml:"components"`
+}
+
+// Parse parses a Config from a YAML or JSON document
+func Parse(data []byte) (Config, error) {
+	var cfg Config
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// NewManager creates an xrun.Manager from cfg, the components are created with the
+// factories they were registered with, see Register. Additional options, such as
+// xrun.Hooks, are applied to the root Manager after the ones of cfg.
+func NewManager(cfg Config, opts ...xrun.Option) (*xrun.Manager, error) {
+	base := []xrun.Option{
+		xrun.ShutdownTimeout(time.Duration(cfg.ShutdownTimeout)),
+		xrun.StartupTimeout(time.Duration(cfg.StartupTimeout)),
+	}
+	if cfg.Name != "" {
+		base = append(base, xrun.Name(cfg.Name))
+	}
+
+	return buildManager(cfg.Components, append(base, opts...))
+}
+
+func buildManager(components []Component, opts []xrun.Option) (*xrun.Manager, error) {
+	enabled, err := enabledComponents(components)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := newDependencies()
+	m := xrun.NewManager(append(opts, xrun.Hooks{OnStateChange: deps.observe})...)
+
+	for _, cc := range enabled {
+		c, err := buildComponent(cc, deps)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", nameOf(cc), err)
+		}
+
+		if err := m.Add(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func buildComponent(cc Component, deps *dependencies) (xrun.Component, error) {
+	var c xrun.Component
+
+	if cc.Type == "" {
+		nested, err := buildManager(cc.Components, []xrun.Option{xrun.ShutdownTimeout(time.Duration(cc.ShutdownTimeout))})
+		if err != nil {
+			return nil, err
+		}
+
+		c = nested
+	} else {
+		factory, err := lookup(cc.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		options := cc.Options
+
+		if c, err = factory(options.Decode); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cc.DependsOn) > 0 {
+		c = deps.after(cc.DependsOn, c)
+	}
+
+	if cc.StartupTimeout > 0 {
+		c = xrun.ReadyWithin(time.Duration(cc.StartupTimeout), c)
+	}
+
+	c = xrun.Named(nameOf(cc), c)
+
+	if cc.Primary {
+		c = xrun.Primary(c)
+	}
+
+	return c, nil
+}
+
+// enabledComponents returns the enabled components after checking
+// that their names are unique and their dependencies are valid
+func enabledComponents(components []Component) ([]Component, error) {
+	var enabled []Component
+
+	byName := make(map[string]Component)
+
+	for _, cc := range components {
+		if cc.Enabled != nil && !*cc.Enabled {
+			continue
+		}
+
+		name := nameOf(cc)
+		if name == "" {
+			return nil, errors.New("component without type or name")
+		}
+
+		if _, dup := byName[name]; dup {
+			return nil, fmt.Errorf("%s: duplicate component name", name)
+		}
+
+		byName[name] = cc
+		enabled = append(enabled, cc)
+	}
+
+	for _, cc := range enabled {
+		for _, dep := range cc.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("%s: depends on %q which is not an enabled component", nameOf(cc), dep)
+			}
+		}
+	}
+
+	if cycle := findCycle(enabled, byName); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %v", cycle)
+	}
+
+	return enabled, nil
+}
+
+// findCycle returns the names of components which depend on each other, if any
+func findCycle(components []Component, byName map[string]Component) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	marks := make(map[string]int)
+
+	var visit func(name string, path []string) []string
+
+	visit = func(name string, path []string) []string {
+		switch marks[name] {
+		case visiting:
+			return append(path, name)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+
+		for _, dep := range byName[name].DependsOn {
+			if cycle := visit(dep, append(path, name)); cycle != nil {
+				return cycle
+			}
+		}
+
+		marks[name] = visited
+
+		return nil
+	}
+
+	for _, cc := range components {
+		if cycle := visit(nameOf(cc), nil); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+func nameOf(cc Component) string {
+	if cc.Name != "" {
+		return cc.Name
+	}
+
+	return cc.Type
+}
+
+// dependencies tracks the components of a Manager which are ready
+type dependencies struct {
+	mu      sync.Mutex
+	ready   map[string]bool
+	changed chan struct{}
+}
+
+func newDependencies() *dependencies {
+	return &dependencies{ready: make(map[string]bool), changed: make(chan struct{})}
+}
+
+func (d *dependencies) observe(s xrun.ComponentStatus) {
+	if s.State != xrun.StateReady && s.State != xrun.StateStopped {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.ready[s.Name] = true
+
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+// after returns a Component which runs c once the components named deps are ready
+func (d *dependencies) after(deps []string, c xrun.Component) xrun.Component {
+	return dependentComponent{Component: xrun.Decorate(c, func(ctx context.Context) error {
+		for {
+			d.mu.Lock()
+			changed := d.changed
+			pending := 0
+
+			for _, dep := range deps {
+				if !d.ready[dep] {
+					pending++
+				}
+			}
+			d.mu.Unlock()
+
+			if pending == 0 {
+				return c.Run(ctx)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-changed:
+			}
+		}
+	}), dependsOn: deps}
+}
+
+// dependentComponent records the dependencies of a Component, see xrun.Description
+type dependentComponent struct {
+	xrun.Component
+	dependsOn []string
+}
+
+// Unwrap returns the underlying Component
+func (d dependentComponent) Unwrap() xrun.Component { return d.Component }
+
+// DependsOn returns the names of the components it depends on
+func (d dependentComponent) DependsOn() []string { return d.dependsOn }
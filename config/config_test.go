@@ -0,0 +1,256 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gojekfarm/xrun"
+)
+
+type recorderOptions struct {
+	Delay time.Duration `yaml:"delay"`
+	Fail  bool          `yaml:"fail"`
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+var (
+	testRecorder     = &recorder{}
+	registerTestOnce sync.Once
+)
+
+func registerTestTypes() {
+	registerTestOnce.Do(func() {
+		Register("test.recorder", func(decode func(v any) error) (xrun.Component, error) {
+			var opts recorderOptions
+			if err := decode(&opts); err != nil {
+				return nil, err
+			}
+
+			if opts.Fail {
+				return nil, errors.New("invalid options")
+			}
+
+			return xrun.ComponentFunc(func(ctx context.Context) error {
+				time.Sleep(opts.Delay)
+
+				testRecorder.record(xrun.Path(ctx))
+				xrun.Ready(ctx)
+
+				return nil
+			}), nil
+		})
+	})
+}
+
+func TestParse(t *testing.T) {
+	yamlDoc := `
+name: orders
+shutdownTimeout: 30s
+components:
+  - type: test.recorder
+    name: api
+    dependsOn: [db]
+    startupTimeout: 1m
+    options:
+      delay: 10ms
+  - type: test.recorder
+    name: db
+    enabled: false
+  - name: workers
+    shutdownTimeout: 5s
+    components:
+      - type: test.recorder
+`
+	jsonDoc := `{
+  "name": "orders",
+  "shutdownTimeout": "30s",
+  "components": [
+    {"type": "test.recorder", "name": "api", "dependsOn": ["db"], "startupTimeout": "1m", "options": {"delay": "10ms"}},
+    {"type": "test.recorder", "name": "db", "enabled": false},
+    {"name": "workers", "shutdownTimeout": "5s", "components": [{"type": "test.recorder"}]}
+  ]
+}`
+
+	for name, doc := range map[string]string{"YAML": yamlDoc, "JSON": jsonDoc} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := Parse([]byte(doc))
+			require.NoError(t, err)
+
+			assert.Equal(t, "orders", cfg.Name)
+			assert.Equal(t, xrun.Duration(30*time.Second), cfg.ShutdownTimeout)
+			require.Len(t, cfg.Components, 3)
+
+			api := cfg.Components[0]
+			assert.Equal(t, []string{"db"}, api.DependsOn)
+			assert.Equal(t, xrun.Duration(time.Minute), api.StartupTimeout)
+
+			var opts recorderOptions
+			require.NoError(t, api.Options.Decode(&opts))
+			assert.Equal(t, 10*time.Millisecond, opts.Delay)
+
+			require.NotNil(t, cfg.Components[1].Enabled)
+			assert.False(t, *cfg.Components[1].Enabled)
+
+			assert.Equal(t, xrun.Duration(5*time.Second), cfg.Components[2].ShutdownTimeout)
+			assert.Len(t, cfg.Components[2].Components, 1)
+		})
+	}
+
+	_, err := Parse([]byte("shutdownTimeout: soon"))
+	assert.ErrorContains(t, err, "parse config")
+}
+
+func TestNewManager(t *testing.T) {
+	registerTestTypes()
+
+	cfg, err := Parse([]byte(`
+name: orders
+components:
+  - type: test.recorder
+    name: api
+    dependsOn: [db]
+  - type: test.recorder
+    name: db
+    options:
+      delay: 50ms
+  - type: test.recorder
+    name: disabled
+    enabled: false
+  - name: workers
+    components:
+      - type: test.recorder
+`))
+	require.NoError(t, err)
+
+	m, err := NewManager(cfg, xrun.ExitWhenAllDone(true))
+	require.NoError(t, err)
+
+	testRecorder.mu.Lock()
+	testRecorder.events = nil
+	testRecorder.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, m.Run(ctx))
+
+	events := testRecorder.Events()
+	assert.ElementsMatch(t, []string{"orders/db", "orders/api", "orders/workers/test.recorder"}, events)
+	assert.Less(t, slices.Index(events, "orders/db"), slices.Index(events, "orders/api"))
+}
+
+func TestNewManagerDescribe(t *testing.T) {
+	registerTestTypes()
+
+	cfg, err := Parse([]byte(`
+components:
+  - type: test.recorder
+    name: api
+    dependsOn: [db]
+    startupTimeout: 5s
+  - type: test.recorder
+    name: db
+    primary: true
+`))
+	require.NoError(t, err)
+
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+
+	d := m.Describe()
+	require.Len(t, d.Components, 2)
+	assert.Equal(t, []string{"db"}, d.Components[0].DependsOn)
+	assert.Equal(t, xrun.Duration(5*time.Second), d.Components[0].StartupTimeout)
+	assert.True(t, d.Components[1].Primary)
+}
+
+func TestNewManagerErrors(t *testing.T) {
+	registerTestTypes()
+
+	testcases := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{
+			name:    "UnknownType",
+			doc:     "components: [{type: unknown}]",
+			wantErr: `unknown: unknown component type "unknown"`,
+		},
+		{
+			name:    "FactoryError",
+			doc:     "components: [{type: test.recorder, options: {fail: true}}]",
+			wantErr: "test.recorder: invalid options",
+		},
+		{
+			name:    "DuplicateName",
+			doc:     "components: [{type: test.recorder}, {type: test.recorder}]",
+			wantErr: "test.recorder: duplicate component name",
+		},
+		{
+			name:    "MissingName",
+			doc:     "components: [{}]",
+			wantErr: "component without type or name",
+		},
+		{
+			name:    "DisabledDependency",
+			doc:     "components: [{type: test.recorder, name: a, dependsOn: [b]}, {type: test.recorder, name: b, enabled: false}]",
+			wantErr: `a: depends on "b" which is not an enabled component`,
+		},
+		{
+			name:    "DependencyCycle",
+			doc:     "components: [{type: test.recorder, name: a, dependsOn: [b]}, {type: test.recorder, name: b, dependsOn: [a]}]",
+			wantErr: "dependency cycle: [a b a]",
+		},
+		{
+			name:    "NestedError",
+			doc:     "components: [{name: workers, components: [{type: unknown}]}]",
+			wantErr: `workers: unknown: unknown component type "unknown"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := Parse([]byte(tc.doc))
+			require.NoError(t, err)
+
+			_, err = NewManager(cfg)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	registerTestTypes()
+
+	assert.Contains(t, Types(), "test.recorder")
+	assert.Panics(t, func() {
+		Register("test.recorder", func(func(any) error) (xrun.Component, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { Register("test.nil", nil) })
+}
@@ -0,0 +1,55 @@
+/*
+Package config creates a tree of managers and components from a YAML or JSON
+document. Types of component are registered with a Factory, which decodes the
+options of the component from the document.
+
+	package main
+
+	import (
+		"context"
+		"net/http"
+		"os"
+		"os/signal"
+
+		"github.com/gojekfarm/xrun"
+		"github.com/gojekfarm/xrun/component"
+		"github.com/gojekfarm/xrun/config"
+	)
+
+	func main() {
+		config.Register("http", func(decode func(v any) error) (xrun.Component, error) {
+			var opts struct {
+				Addr string `yaml:"addr"`
+			}
+
+			if err := decode(&opts); err != nil {
+				return nil, err
+			}
+
+			return component.HTTPServer(component.HTTPServerOptions{Server: &http.Server{Addr: opts.Addr}}), nil
+		})
+
+		data, err := os.ReadFile("xrun.yaml")
+		if err != nil {
+			os.Exit(1)
+		}
+
+		cfg, err := config.Parse(data)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		m, err := config.NewManager(cfg)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := m.Run(ctx); err != nil {
+			os.Exit(1)
+		}
+	}
+*/
+package config
@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+	"github.com/gojekfarm/xrun/config"
+)
+
+func ExampleNewManager() {
+	config.Register("http", func(decode func(v any) error) (xrun.Component, error) {
+		var opts struct {
+			Addr string `yaml:"addr"`
+		}
+
+		if err := decode(&opts); err != nil {
+			return nil, err
+		}
+
+		return component.HTTPServer(component.HTTPServerOptions{
+			Server: &http.Server{Addr: opts.Addr},
+		}), nil
+	})
+
+	cfg, err := config.Parse([]byte(`
+name: orders
+shutdownTimeout: 30s
+components:
+  - type: http
+    name: api
+    options:
+      addr: ":8080"
+  - type: http
+    name: metrics
+    enabled: false
+    options:
+      addr: ":9090"
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	m, err := config.NewManager(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
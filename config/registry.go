@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// Factory creates an xrun.Component of a registered type from its options in a Config,
+// decode decodes the options into v, see gopkg.in/yaml.v3 for the struct tags
+type Factory func(decode func(v any) error) (xrun.Component, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a type of Component available to NewManager,
+// it panics if the type is already registered or factory is nil
+func Register(typ string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("config: Register factory is nil")
+	}
+
+	if _, dup := registry[typ]; dup {
+		panic("config: Register called twice for type " + typ)
+	}
+
+	registry[typ] = factory
+}
+
+// Types returns the sorted list of the registered types of Component
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+func lookup(typ string) (Factory, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	f, ok := registry[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown component type %q", typ)
+	}
+
+	return f, nil
+}
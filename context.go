@@ -0,0 +1,125 @@
+package xrun
+
+import (
+	"context"
+	"log/slog"
+)
+
+const defaultManagerName = "root"
+
+// ComponentName returns the name of the Component running with ctx, see Named.
+// It returns an empty string when ctx was not passed to Run by a Manager.
+func ComponentName(ctx context.Context) string {
+	if cs, ok := stateFrom(ctx); ok {
+		return cs.name
+	}
+
+	return ""
+}
+
+// Path returns the path of the Component running with ctx in the tree of managers,
+// example: "root/api/http" for the component "http" of the Manager "api" added to
+// the Manager "root", see Name. It returns an empty string when ctx was not passed
+// to Run by a Manager.
+func Path(ctx context.Context) string {
+	if cs, ok := stateFrom(ctx); ok {
+		return cs.path
+	}
+
+	return ""
+}
+
+// Logger returns a logger for the Component running with ctx, which carries its name
+// and path as the "component" and "path" attributes, see WithLogger. It returns
+// slog.Default when ctx was not passed to Run by a Manager.
+func Logger(ctx context.Context) *slog.Logger {
+	if cs, ok := stateFrom(ctx); ok {
+		return cs.logger
+	}
+
+	return slog.Default()
+}
+
+func stateFrom(ctx context.Context) (*componentState, bool) {
+	cs, ok := ctx.Value(componentStateKey{}).(*componentState)
+
+	return cs, ok
+}
+
+// initMetadata sets the path and the base logger of a Manager from the Component
+// it runs as, if any, or from its own options. A nested Manager is linked to that
+// Component, so that it's reached even when hidden in a closure, example: All.
+func (m *Manager) initMetadata(ctx context.Context) {
+	parent, nested := stateFrom(ctx)
+
+	switch {
+	case nested:
+		m.path = parent.path
+		parent.m.addNested(parent, m)
+	case m.name != "":
+		m.path = m.name
+	default:
+		m.path = defaultManagerName
+	}
+
+	switch {
+	case m.logger != nil:
+		m.baseLogger = m.logger
+	case nested:
+		m.baseLogger = parent.m.baseLogger
+	default:
+		m.baseLogger = slog.Default()
+	}
+}
+
+func (m *Manager) addNested(cs *componentState, nested *Manager) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	for _, n := range cs.nested {
+		if n == nested {
+			return
+		}
+	}
+
+	cs.nested = append(cs.nested, nested)
+}
+
+// nested returns the managers run by the i-th Component, once they have started
+func (m *Manager) nested(i int) []*Manager {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	if i >= len(m.states) || m.states[i] == nil {
+		return nil
+	}
+
+	return append([]*Manager(nil), m.states[i].nested...)
+}
+
+// nestedManager returns the Manager added as the i-th Component, or the one
+// it runs once started, it's not found when the Component runs several
+func (m *Manager) nestedManager(c Component, i int) (*Manager, bool) {
+	if nested, ok := find[*Manager](c); ok {
+		return nested, true
+	}
+
+	if nested := m.nested(i); len(nested) == 1 {
+		return nested[0], true
+	}
+
+	return nil, false
+}
+
+func (m *Manager) newComponentState(c Component, i int) *componentState {
+	name := nameOf(c, i)
+	path := m.path + "/" + name
+
+	return &componentState{
+		m:      m,
+		name:   name,
+		path:   path,
+		logger: m.baseLogger.With("component", name, "path", path),
+		state:  StateStarting,
+	}
+}
@@ -0,0 +1,116 @@
+package xrun
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentMetadata(t *testing.T) {
+	type metadata struct {
+		name, path string
+	}
+
+	testcases := []struct {
+		name    string
+		options []Option
+		nested  bool
+		want    metadata
+	}{
+		{
+			name: "DefaultRoot",
+			want: metadata{name: "http", path: "root/http"},
+		},
+		{
+			name:    "NamedRoot",
+			options: []Option{Name("orders")},
+			want:    metadata{name: "http", path: "orders/http"},
+		},
+		{
+			name:   "Nested",
+			nested: true,
+			want:   metadata{name: "http", path: "root/api/http"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				got metadata
+				buf bytes.Buffer
+			)
+
+			c := Named("http", ComponentFunc(func(ctx context.Context) error {
+				got = metadata{name: ComponentName(ctx), path: Path(ctx)}
+				Logger(ctx).Info("hello")
+
+				return nil
+			}))
+
+			if tc.nested {
+				api := NewManager(ExitWhenAllDone(true))
+				require.NoError(t, api.Add(c))
+
+				c = Named("api", api)
+			}
+
+			opts := append([]Option{WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))}, tc.options...)
+			m := NewManager(append(opts, ExitWhenAllDone(true))...)
+
+			require.NoError(t, m.Add(c))
+			require.NoError(t, m.Run(context.Background()))
+
+			assert.Equal(t, tc.want, got)
+			assert.Contains(t, buf.String(), "msg=hello component="+tc.want.name+" path="+tc.want.path+"\n")
+		})
+	}
+}
+
+func TestComponentMetadataWithoutManager(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Empty(t, ComponentName(ctx))
+	assert.Empty(t, Path(ctx))
+	assert.Equal(t, slog.Default(), Logger(ctx))
+}
+
+func TestDefaultComponentName(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+
+	record := ComponentFunc(func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		paths = append(paths, Path(ctx))
+
+		return nil
+	})
+
+	m := NewManager(ExitWhenAllDone(true))
+	require.NoError(t, m.Add(record))
+	require.NoError(t, m.Add(record))
+	require.NoError(t, m.Run(context.Background()))
+
+	assert.ElementsMatch(t, []string{"root/component-1", "root/component-2"}, paths)
+}
+
+func TestLoggingWithComponentLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	m := NewManager(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))), ExitWhenAllDone(true))
+	require.NoError(t, m.Add(Wrap(Named("worker", ComponentFunc(func(ctx context.Context) error {
+		return nil
+	})), Logging(nil))))
+	require.NoError(t, m.Run(context.Background()))
+
+	assert.Contains(t, buf.String(), `msg="component started" component=worker path=root/worker`)
+	assert.Contains(t, buf.String(), `msg="component stopped" component=worker path=root/worker`)
+}
@@ -0,0 +1,238 @@
+package xrun
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Description describes a Manager or one of its components, see Manager.Describe
+type Description struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Order is the position at which the Component was added to its Manager, starting at 1
+	Order int `json:"order,omitempty"`
+	// State is the current State, empty when the Component has not been started
+	State string `json:"state,omitempty"`
+	// Primary reports whether the Component is primary, see Primary
+	Primary bool `json:"primary,omitempty"`
+	// DependsOn are the names of the components it depends on, reported by
+	// a Component with a DependsOn() []string method, see the config package
+	DependsOn       []string `json:"dependsOn,omitempty"`
+	StartupTimeout  Duration `json:"startupTimeout,omitempty"`
+	ShutdownTimeout Duration `json:"shutdownTimeout,omitempty"`
+	WatchdogTimeout Duration `json:"watchdogTimeout,omitempty"`
+	// Manager reports whether it describes a Manager, with its components in Components
+	Manager    bool          `json:"manager,omitempty"`
+	Components []Description `json:"components,omitempty"`
+}
+
+// dependent is implemented by a Component which depends on other components
+type dependent interface {
+	DependsOn() []string
+}
+
+// Describe returns the tree of components of the Manager, including nested managers
+// added as components. The states are set once Run has started, and so are managers
+// run inside a Component, such as the ones composed with All.
+func (m *Manager) Describe() Description {
+	m.mu.Lock()
+	path := m.path
+	m.mu.Unlock()
+
+	name := m.name
+	if name == "" {
+		name = defaultManagerName
+	}
+
+	if path == "" {
+		path = name
+	}
+
+	return m.describe(Description{Name: name, Path: path})
+}
+
+func (m *Manager) describe(d Description) Description {
+	m.mu.Lock()
+	components := append([]Component(nil), m.components...)
+	m.mu.Unlock()
+
+	m.statusMu.Lock()
+	states := make([]string, len(components))
+
+	for i, cs := range m.states {
+		if cs != nil && i < len(states) {
+			states[i] = cs.state.String()
+		}
+	}
+	m.statusMu.Unlock()
+
+	d.Manager = true
+	d.ShutdownTimeout = Duration(m.shutdownTimeout)
+
+	if d.StartupTimeout == 0 {
+		d.StartupTimeout = Duration(m.startupTimeout)
+	}
+
+	for i, c := range components {
+		if c == nil {
+			continue
+		}
+
+		name := nameOf(c, i)
+		cd := Description{
+			Name:    name,
+			Path:    d.Path + "/" + name,
+			Order:   i + 1,
+			State:   states[i],
+			Primary: isPrimary(c),
+		}
+
+		if dep, ok := find[dependent](c); ok {
+			cd.DependsOn = dep.DependsOn()
+		}
+
+		if r, ok := find[readyWithinComponent](c); ok {
+			cd.StartupTimeout = Duration(r.timeout)
+		}
+
+		if w, ok := find[watchdogComponent](c); ok {
+			cd.WatchdogTimeout = Duration(w.opts.Timeout)
+		}
+
+		if nested, ok := m.nestedManager(c, i); ok {
+			cd = nested.describe(cd)
+		}
+
+		d.Components = append(d.Components, cd)
+	}
+
+	return d
+}
+
+// DOT renders the Description as a Graphviz digraph. Managers are linked
+// to their components, with the order in which they were added, and
+// dependencies are drawn as dashed edges.
+func (d Description) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph xrun {\n\tnode [shape=box];\n")
+
+	ids := d.ids()
+
+	d.walk(func(n Description) {
+		shape := ""
+		if n.Manager {
+			shape = ", shape=folder"
+		}
+
+		fmt.Fprintf(&b, "\t%s [label=%s%s];\n", ids[n.Path], strconv.Quote(strings.Join(n.labels(), "\n")), shape)
+	})
+
+	d.edges(ids, func(from, to, label string, dependency bool) {
+		style := ""
+		if dependency {
+			style = "style=dashed, "
+		}
+
+		fmt.Fprintf(&b, "\t%s -> %s [%slabel=%s];\n", from, to, style, strconv.Quote(label))
+	})
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid renders the Description as a Mermaid flowchart, see DOT
+func (d Description) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("flowchart TD\n")
+
+	ids := d.ids()
+
+	d.walk(func(n Description) {
+		label := strings.ReplaceAll(strings.Join(n.labels(), "<br/>"), `"`, "#quot;")
+
+		if n.Manager {
+			fmt.Fprintf(&b, "\t%s[[\"%s\"]]\n", ids[n.Path], label)
+		} else {
+			fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[n.Path], label)
+		}
+	})
+
+	d.edges(ids, func(from, to, label string, dependency bool) {
+		arrow := "-->"
+		if dependency {
+			arrow = "-.->"
+		}
+
+		fmt.Fprintf(&b, "\t%s %s|%s| %s\n", from, arrow, label, to)
+	})
+
+	return b.String()
+}
+
+// walk calls fn for d and all of its components, depth first
+func (d Description) walk(fn func(Description)) {
+	fn(d)
+
+	for _, c := range d.Components {
+		c.walk(fn)
+	}
+}
+
+// ids returns identifiers for the nodes of the graph by path
+func (d Description) ids() map[string]string {
+	ids := make(map[string]string)
+
+	d.walk(func(n Description) {
+		ids[n.Path] = "n" + strconv.Itoa(len(ids))
+	})
+
+	return ids
+}
+
+func (d Description) edges(ids map[string]string, fn func(from, to, label string, dependency bool)) {
+	d.walk(func(n Description) {
+		for _, c := range n.Components {
+			fn(ids[n.Path], ids[c.Path], strconv.Itoa(c.Order), false)
+		}
+
+		for _, c := range n.Components {
+			for _, dep := range c.DependsOn {
+				if to, ok := ids[n.Path+"/"+dep]; ok {
+					fn(ids[c.Path], to, "depends on", true)
+				}
+			}
+		}
+	})
+}
+
+func (d Description) labels() []string {
+	labels := []string{d.Name}
+
+	if d.State != "" {
+		labels = append(labels, "state: "+d.State)
+	}
+
+	if d.Primary {
+		labels = append(labels, "primary")
+	}
+
+	for _, t := range []struct {
+		name string
+		d    Duration
+	}{
+		{"startup timeout", d.StartupTimeout},
+		{"shutdown timeout", d.ShutdownTimeout},
+		{"watchdog", d.WatchdogTimeout},
+	} {
+		if t.d > 0 {
+			labels = append(labels, t.name+": "+time.Duration(t.d).String())
+		}
+	}
+
+	return labels
+}
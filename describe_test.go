@@ -0,0 +1,173 @@
+package xrun
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func describedManager(t *testing.T, opts ...Option) *Manager {
+	t.Helper()
+
+	block := ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+
+		return nil
+	})
+
+	m := NewManager(append([]Option{Name("orders"), ShutdownTimeout(30 * time.Second)}, opts...)...)
+	require.NoError(t, m.Add(Primary(Named("api", Watchdog(WatchdogOptions{Timeout: 10 * time.Second}, block)))))
+	require.NoError(t, m.Add(ReadyWithin(5*time.Second, Named("db", block))))
+
+	workers := NewManager(ShutdownTimeout(5 * time.Second))
+	require.NoError(t, workers.Add(Named("consumer", block)))
+	require.NoError(t, m.Add(Named("workers", workers)))
+
+	return m
+}
+
+func TestDescribe(t *testing.T) {
+	d := describedManager(t).Describe()
+
+	assert.Equal(t, Description{
+		Name:            "orders",
+		Path:            "orders",
+		ShutdownTimeout: Duration(30 * time.Second),
+		Manager:         true,
+		Components: []Description{
+			{Name: "api", Path: "orders/api", Order: 1, Primary: true, WatchdogTimeout: Duration(10 * time.Second)},
+			{Name: "db", Path: "orders/db", Order: 2, StartupTimeout: Duration(5 * time.Second)},
+			{
+				Name:            "workers",
+				Path:            "orders/workers",
+				Order:           3,
+				ShutdownTimeout: Duration(5 * time.Second),
+				Manager:         true,
+				Components: []Description{
+					{Name: "consumer", Path: "orders/workers/consumer", Order: 1},
+				},
+			},
+		},
+	}, d)
+
+	b, err := json.Marshal(d.Components[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"db","path":"orders/db","order":2,"startupTimeout":"5s"}`, string(b))
+}
+
+func TestDescribeStates(t *testing.T) {
+	ready := make(chan struct{})
+
+	m := describedManager(t, Hooks{OnReady: func() { close(ready) }})
+	require.NoError(t, m.Add(Named("migrations", ComponentFunc(func(ctx context.Context) error { return nil }))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	<-ready
+
+	d := m.Describe()
+	assert.Equal(t, "ready", d.Components[0].State)
+	assert.Equal(t, "ready", d.Components[2].State)
+	assert.Equal(t, "ready", d.Components[2].Components[0].State)
+	assert.Equal(t, "stopped", d.Components[3].State)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+type dependsOn struct {
+	Component
+	names []string
+}
+
+func (d dependsOn) Unwrap() Component { return d.Component }
+
+func (d dependsOn) DependsOn() []string { return d.names }
+
+func TestDescribeAll(t *testing.T) {
+	ready := make(chan struct{})
+
+	m := NewManager(Hooks{OnReady: func() { close(ready) }})
+	require.NoError(t, m.Add(Named("workers", All(5*time.Second, Named("consumer", ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+
+		return nil
+	}))))))
+
+	assert.False(t, m.Describe().Components[0].Manager, "not started yet")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	<-ready
+
+	workers := m.Describe().Components[0]
+	assert.True(t, workers.Manager)
+	assert.Equal(t, Duration(5*time.Second), workers.ShutdownTimeout)
+	assert.Equal(t, []Description{
+		{Name: "consumer", Path: "root/workers/consumer", Order: 1, State: "ready"},
+	}, workers.Components)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+func TestDescribeDependencies(t *testing.T) {
+	run := ComponentFunc(func(ctx context.Context) error { return nil })
+
+	m := NewManager()
+	require.NoError(t, m.Add(Named("api", dependsOn{Component: run, names: []string{"db"}})))
+	require.NoError(t, m.Add(Named("db", run)))
+
+	d := m.Describe()
+	assert.Equal(t, []string{"db"}, d.Components[0].DependsOn)
+	assert.Contains(t, d.DOT(), "\tn1 -> n2 [style=dashed, label=\"depends on\"];\n")
+	assert.Contains(t, d.Mermaid(), "\tn1 -.->|depends on| n2\n")
+}
+
+func TestDescriptionDOT(t *testing.T) {
+	want := `digraph xrun {
+	node [shape=box];
+	n0 [label="orders\nshutdown timeout: 30s", shape=folder];
+	n1 [label="api\nprimary\nwatchdog: 10s"];
+	n2 [label="db\nstartup timeout: 5s"];
+	n3 [label="workers\nshutdown timeout: 5s", shape=folder];
+	n4 [label="consumer"];
+	n0 -> n1 [label="1"];
+	n0 -> n2 [label="2"];
+	n0 -> n3 [label="3"];
+	n3 -> n4 [label="1"];
+}
+`
+
+	assert.Equal(t, want, describedManager(t).Describe().DOT())
+}
+
+func TestDescriptionMermaid(t *testing.T) {
+	want := `flowchart TD
+	n0[["orders<br/>shutdown timeout: 30s"]]
+	n1["api<br/>primary<br/>watchdog: 10s"]
+	n2["db<br/>startup timeout: 5s"]
+	n3[["workers<br/>shutdown timeout: 5s"]]
+	n4["consumer"]
+	n0 -->|1| n1
+	n0 -->|2| n2
+	n0 -->|3| n3
+	n3 -->|1| n4
+`
+
+	assert.Equal(t, want, describedManager(t).Describe().Mermaid())
+}
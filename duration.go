@@ -0,0 +1,23 @@
+package xrun
+
+import "time"
+
+// Duration is a time.Duration which is marshalled as a string, example: "30s"
+type Duration time.Duration
+
+// UnmarshalText parses a duration with time.ParseDuration
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalText formats the duration with time.Duration.String
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
@@ -2,9 +2,14 @@ package xrun_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gojekfarm/xrun"
 	"github.com/gojekfarm/xrun/component"
@@ -86,3 +91,310 @@ func ExampleAll() {
 		os.Exit(1)
 	}
 }
+
+func ExamplePrimary() {
+	// Run returns as soon as the migration is done, the metrics server is then shutdown gracefully
+	if err := xrun.All(xrun.NoTimeout,
+		xrun.Primary(xrun.ComponentFunc(func(ctx context.Context) error {
+			// Run a one-shot job here and return once it's done
+			return nil
+		})),
+		component.HTTPServer(component.HTTPServerOptions{Server: &http.Server{}}),
+	).Run(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleJob() {
+	job := xrun.ComponentFunc(func(ctx context.Context) error {
+		// Run the job here and return once it's done
+		return nil
+	})
+	metrics := component.HTTPServer(component.HTTPServerOptions{Server: &http.Server{}})
+
+	// metrics server is shutdown gracefully once the job returns
+	if err := xrun.Job(job, metrics).Run(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleReady() {
+	m := xrun.NewManager(xrun.Hooks{
+		OnReady: func() {
+			fmt.Println("all components are ready")
+		},
+	})
+
+	if err := m.Add(xrun.Named("worker", xrun.ComponentFunc(func(ctx context.Context) error {
+		// Connect to dependencies here and signal readiness once done
+		xrun.Ready(ctx)
+		<-ctx.Done()
+		return nil
+	}))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+type config struct{}
+
+func (config) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (config) Reload(ctx context.Context) error {
+	// Read the configuration again and apply it
+	return nil
+}
+
+func ExampleReloadOnSignal() {
+	m := xrun.NewManager(
+		xrun.ReloadOnSignal{syscall.SIGHUP},
+		xrun.Hooks{
+			OnReload: func(err error) {
+				fmt.Println("reloaded components, error:", err)
+			},
+		},
+	)
+
+	if err := m.Add(xrun.Named("config", config{})); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleStackDump() {
+	m := xrun.NewManager(
+		xrun.ShutdownTimeout(30*time.Second),
+		xrun.StackDump{
+			Writer:  os.Stderr,
+			Signals: []os.Signal{syscall.SIGQUIT},
+		},
+	)
+
+	if err := m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{},
+	}))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		var tErr *xrun.ShutdownTimeoutError
+		if errors.As(err, &tErr) {
+			fmt.Println("stuck components:", tErr.Components)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func ExampleWatchdog() {
+	m := xrun.NewManager(xrun.Hooks{
+		OnMissedHeartbeat: func(name string) {
+			fmt.Println("restarting stuck component:", name)
+		},
+	})
+
+	consumer := xrun.ComponentFunc(func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+				// poll and process messages
+				xrun.Heartbeat(ctx)
+			}
+		}
+	})
+
+	if err := m.Add(xrun.Watchdog(xrun.WatchdogOptions{
+		Timeout: 10 * time.Second,
+		Policy:  xrun.WatchdogRestart,
+	}, xrun.Named("consumer", consumer))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleStartupTimeout() {
+	m := xrun.NewManager(
+		xrun.StartupTimeout(10*time.Second),
+		xrun.ShutdownTimeout(30*time.Second),
+	)
+
+	if err := m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{},
+	}))); err != nil {
+		panic(err)
+	}
+
+	// cache warmup is allowed to take longer than the other components
+	if err := m.Add(xrun.ReadyWithin(time.Minute, xrun.Named("cache", xrun.ComponentFunc(
+		func(ctx context.Context) error {
+			// load the cache
+			xrun.Ready(ctx)
+
+			<-ctx.Done()
+
+			return nil
+		},
+	)))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		if errors.Is(err, xrun.ErrNotReady) {
+			fmt.Println("startup failed:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func ExampleWrap() {
+	m := xrun.NewManager()
+
+	consumer := xrun.ComponentFunc(func(ctx context.Context) error {
+		// consume messages until ctx is closed
+		<-ctx.Done()
+		return nil
+	})
+
+	if err := m.Add(xrun.Wrap(
+		xrun.Named("consumer", consumer),
+		xrun.Logging(slog.Default()),
+		xrun.Recover(),
+		xrun.Retry(xrun.RetryOptions{MaxAttempts: 5, MaxBackoff: 10 * time.Second}),
+		xrun.Delay(time.Second),
+	)); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleLogger() {
+	api := xrun.NewManager()
+
+	if err := api.Add(xrun.Named("http", xrun.ComponentFunc(func(ctx context.Context) error {
+		// logs with component=http path=orders/api/http
+		xrun.Logger(ctx).Info("starting", "name", xrun.ComponentName(ctx), "path", xrun.Path(ctx))
+
+		<-ctx.Done()
+
+		return nil
+	}))); err != nil {
+		panic(err)
+	}
+
+	m := xrun.NewManager(xrun.Name("orders"), xrun.WithLogger(slog.Default()))
+
+	if err := m.Add(xrun.Named("api", api)); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleManager_Describe() {
+	m := xrun.NewManager(xrun.Name("orders"), xrun.ShutdownTimeout(30*time.Second))
+
+	if err := m.Add(xrun.Named("api", xrun.Primary(xrun.ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})))); err != nil {
+		panic(err)
+	}
+
+	if err := m.Add(xrun.Named("db", xrun.ReadyWithin(5*time.Second, xrun.ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})))); err != nil {
+		panic(err)
+	}
+
+	fmt.Print(m.Describe().Mermaid())
+	// Output:
+	// flowchart TD
+	// 	n0[["orders<br/>shutdown timeout: 30s"]]
+	// 	n1["api<br/>primary"]
+	// 	n2["db<br/>startup timeout: 5s"]
+	// 	n0 -->|1| n1
+	// 	n0 -->|2| n2
+}
+
+func ExampleApp() {
+	app := &xrun.App{
+		Name:            "orders",
+		ShutdownTimeout: 30 * time.Second,
+		DrainDelay:      5 * time.Second,
+		Init: func(ctx context.Context, m *xrun.Manager) error {
+			return m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{
+				Server: &http.Server{Addr: ":8080"},
+			})))
+		},
+	}
+
+	// orders -shutdown-timeout 1m -log-level debug
+	app.Main()
+}
+
+func ExampleManager_ShutdownReport() {
+	m := xrun.NewManager(xrun.ShutdownTimeout(30 * time.Second))
+
+	if err := m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{Addr: ":8080"},
+	}))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := m.Run(ctx)
+
+	// COMPONENT  SIGNALLED     RETURNED      DURATION  BUDGET  EXCEEDED  ERROR
+	// root/http  15:04:05.000  15:04:05.120  120ms     30s     no        -
+	fmt.Print(m.ShutdownReport())
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,145 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMissedHeartbeat is returned by Manager.Run when a Component run with
+// Watchdog and WatchdogShutdown has not called Heartbeat within the timeout
+var ErrMissedHeartbeat = errors.New("missed heartbeat")
+
+// Heartbeat signals that the Component running with ctx is making progress,
+// see Watchdog. It must be called with the context passed to Run,
+// calling it with any other context is a no-op.
+func Heartbeat(ctx context.Context) {
+	if cs, ok := stateFrom(ctx); ok {
+		cs.heartbeat()
+	}
+}
+
+// WatchdogPolicy decides what Manager does when a Component misses its heartbeat
+type WatchdogPolicy int
+
+const (
+	// WatchdogShutdown fails the Component with ErrMissedHeartbeat,
+	// which makes Manager.Run return and shutdown all the components
+	WatchdogShutdown WatchdogPolicy = iota
+	// WatchdogRestart cancels the Component and runs it again once it returns
+	WatchdogRestart
+)
+
+// WatchdogOptions holds options for Watchdog
+type WatchdogOptions struct {
+	// Timeout is the maximum duration between heartbeats,
+	// counted from the start of the Component
+	Timeout time.Duration
+	// Policy decides what happens when the heartbeat is missed
+	Policy WatchdogPolicy
+}
+
+// Watchdog makes Manager detect a Component which is stuck, it must call Heartbeat
+// at least once every Timeout while it's running. Missed heartbeats are reported
+// to Hooks.OnMissedHeartbeat and handled according to the WatchdogPolicy.
+func Watchdog(opts WatchdogOptions, c Component) Component {
+	return watchdogComponent{Component: c, opts: opts}
+}
+
+type watchdogComponent struct {
+	Component
+	opts WatchdogOptions
+}
+
+// Unwrap returns the underlying Component
+func (w watchdogComponent) Unwrap() Component { return w.Component }
+
+// runComponent runs c, restarting it on missed heartbeats with WatchdogRestart
+func (m *Manager) runComponent(ctx context.Context, c Component, cs *componentState) error {
+	w, ok := find[watchdogComponent](c)
+	if !ok || w.opts.Timeout <= 0 {
+		return c.Run(ctx)
+	}
+
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		missed := make(chan struct{})
+
+		cs.heartbeat()
+
+		m.wg.Add(1)
+		go m.watch(runCtx, cancel, cs, w.opts, missed)
+
+		err := c.Run(runCtx)
+
+		cancel()
+
+		select {
+		case <-missed:
+			if w.opts.Policy == WatchdogRestart && ctx.Err() == nil {
+				continue
+			}
+		default:
+		}
+
+		return err
+	}
+}
+
+func (m *Manager) watch(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	cs *componentState,
+	opts WatchdogOptions,
+	missed chan<- struct{},
+) {
+	defer m.wg.Done()
+
+	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if wait := time.Until(cs.lastHeartbeat().Add(opts.Timeout)); wait > 0 {
+			timer.Reset(wait)
+
+			continue
+		}
+
+		if m.isHalting() {
+			return
+		}
+
+		for _, h := range m.hooks {
+			if h.OnMissedHeartbeat != nil {
+				h.OnMissedHeartbeat(cs.name)
+			}
+		}
+
+		close(missed)
+
+		if opts.Policy == WatchdogRestart {
+			cancel()
+
+			return
+		}
+
+		m.setState(cs, StateFailed)
+		m.errChan <- fmt.Errorf("%s: %w within %s", cs.name, ErrMissedHeartbeat, opts.Timeout)
+
+		return
+	}
+}
+
+func (m *Manager) isHalting() bool {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	return m.halting
+}
@@ -0,0 +1,112 @@
+package xrun
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog(t *testing.T) {
+	testcases := []struct {
+		name       string
+		policy     WatchdogPolicy
+		beats      int
+		wantErr    string
+		wantMissed bool
+		wantRuns   int32
+	}{
+		{
+			name:  "Healthy",
+			beats: -1,
+		},
+		{
+			name:       "ShutdownOnMissedHeartbeat",
+			policy:     WatchdogShutdown,
+			beats:      3,
+			wantErr:    "worker: missed heartbeat within 40ms",
+			wantMissed: true,
+		},
+		{
+			name:       "RestartOnMissedHeartbeat",
+			policy:     WatchdogRestart,
+			beats:      0,
+			wantMissed: true,
+			wantRuns:   2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				missed []string
+				runs   atomic.Int32
+			)
+
+			m := NewManager(Hooks{OnMissedHeartbeat: func(name string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				missed = append(missed, name)
+			}})
+
+			require.NoError(t, m.Add(Watchdog(
+				WatchdogOptions{Timeout: 40 * time.Millisecond, Policy: tc.policy},
+				Named("worker", ComponentFunc(func(ctx context.Context) error {
+					runs.Add(1)
+
+					ticker := time.NewTicker(10 * time.Millisecond)
+					defer ticker.Stop()
+
+					for i := 0; tc.beats < 0 || i < tc.beats; i++ {
+						select {
+						case <-ctx.Done():
+							return nil
+						case <-ticker.C:
+							Heartbeat(ctx)
+						}
+					}
+
+					// stuck, but still honours cancellation
+					<-ctx.Done()
+
+					return nil
+				})),
+			)))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			err := m.Run(ctx)
+
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, ErrMissedHeartbeat)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if tc.wantMissed {
+				assert.Contains(t, missed, "worker")
+			} else {
+				assert.Empty(t, missed)
+			}
+
+			if tc.wantRuns > 0 {
+				assert.GreaterOrEqual(t, runs.Load(), tc.wantRuns)
+			}
+		})
+	}
+}
+
+func TestHeartbeatWithoutManager(t *testing.T) {
+	assert.NotPanics(t, func() { Heartbeat(context.Background()) })
+}
@@ -3,8 +3,11 @@ package xrun
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log/slog"
+	"os"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -32,9 +35,29 @@ type Manager struct {
 
 	started         bool
 	stopping        bool
+	exitWhenAllDone bool
 	shutdownTimeout time.Duration
+	startupTimeout  time.Duration
 	shutdownCtx     context.Context
 	errChan         chan error
+
+	pending atomic.Int32
+	doneCh  chan struct{}
+
+	name       string
+	path       string
+	logger     *slog.Logger
+	baseLogger *slog.Logger
+
+	hooks         []Hooks
+	reloadSignals []os.Signal
+	stackDump     *StackDump
+	runCtx        context.Context
+
+	statusMu   sync.Mutex
+	states     []*componentState
+	readyFired bool
+	halting    bool
 }
 
 // Add will enqueue the Component to run it,
@@ -58,9 +81,16 @@ func (m *Manager) Add(c Component) error {
 
 // Run starts running the registered components. The components will stop running
 // when the context is closed. Run blocks until the context is closed or
-// an error occurs.
+// an error occurs. With ExitWhenAllDone, or when Primary components are registered,
+// Run also returns once those components have returned on their own.
 func (m *Manager) Run(ctx context.Context) (err error) {
-	m.internalCtx, m.internalCancel = context.WithCancel(ctx)
+	m.mu.Lock()
+	m.runCtx = ctx
+	m.initMetadata(ctx)
+	m.mu.Unlock()
+
+	// components are cancelled by engageStopProcedure, after the Hooks are notified
+	m.internalCtx, m.internalCancel = context.WithCancel(uncancelled{ctx})
 
 	defer func() {
 		if stopErr := m.engageStopProcedure(); stopErr != nil {
@@ -69,37 +99,146 @@ func (m *Manager) Run(ctx context.Context) (err error) {
 	}()
 
 	m.errChan = make(chan error)
+	m.doneCh = make(chan struct{})
 
 	go m.start()
 
+	if len(m.reloadSignals) > 0 {
+		go m.reloadOnSignal(m.internalCtx)
+	}
+
+	if m.stackDump != nil && len(m.stackDump.Signals) > 0 {
+		go m.dumpOnSignal(m.internalCtx)
+	}
+
 	select {
 	case <-ctx.Done():
 		return
 	case err := <-m.errChan:
 		return err
+	case <-m.doneCh:
+		return
 	}
 }
 
 func (m *Manager) start() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	if m.stopping {
+		return
+	}
+
 	m.started = true
 
-	for _, c := range m.components {
+	tracked, n := m.trackedComponents()
+
+	if tracked != nil {
+		m.pending.Store(n)
+
+		if n == 0 {
+			close(m.doneCh)
+		}
+	}
+
+	m.initStates()
+	m.watchStartup()
+
+	for i, c := range m.components {
 		if c != nil {
-			m.startComponent(c)
+			m.startComponent(c, m.states[i], tracked != nil && tracked[i])
 		}
 	}
 }
 
-func (m *Manager) startComponent(c Component) {
+func (m *Manager) initStates() {
+	m.statusMu.Lock()
+
+	m.states = make([]*componentState, len(m.components))
+
+	var started []ComponentStatus
+
+	for i, c := range m.components {
+		if c != nil {
+			m.states[i] = m.newComponentState(c, i)
+			started = append(started, ComponentStatus{Name: m.states[i].name, State: StateStarting})
+		}
+	}
+
+	ready := m.becameReady()
+
+	m.statusMu.Unlock()
+
+	for _, cs := range started {
+		m.stateChanged(cs)
+	}
+
+	if ready {
+		m.ready()
+	}
+}
+
+// trackedComponents reports which components must return for Run to return,
+// it returns nil when Run should only return on cancellation or error.
+func (m *Manager) trackedComponents() ([]bool, int32) {
+	all := make([]bool, len(m.components))
+	primaries := make([]bool, len(m.components))
+
+	var nAll, nPrimaries int32
+
+	for i, c := range m.components {
+		if c == nil {
+			continue
+		}
+
+		all[i] = true
+		nAll++
+
+		if isPrimary(c) {
+			primaries[i] = true
+			nPrimaries++
+		}
+	}
+
+	switch {
+	case nPrimaries > 0:
+		return primaries, nPrimaries
+	case m.exitWhenAllDone:
+		return all, nAll
+	default:
+		return nil, 0
+	}
+}
+
+func (m *Manager) startComponent(c Component, cs *componentState, tracked bool) {
 	m.wg.Add(1)
 
 	go func() {
 		defer m.wg.Done()
 
-		if err := c.Run(m.internalCtx); err != nil && !errors.Is(err, context.Canceled) {
+		ctx := context.WithValue(m.internalCtx, componentStateKey{}, cs)
+
+		var err error
+
+		pprof.Do(ctx, pprof.Labels(componentLabel, cs.path), func(ctx context.Context) {
+			err = m.runComponent(ctx, c, cs)
+		})
+
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+
+		m.returned(cs, err)
+
+		if err != nil {
+			m.setState(cs, StateFailed)
 			m.errChan <- err
+		} else {
+			m.setState(cs, StateStopped)
+		}
+
+		if tracked && m.pending.Add(-1) == 0 {
+			close(m.doneCh)
 		}
 	}()
 }
@@ -108,6 +247,7 @@ func (m *Manager) engageStopProcedure() error {
 	shutdownCancel := m.cancelFunc()
 	defer shutdownCancel()
 
+	m.halt()
 	m.internalCancel()
 
 	m.mu.Lock()
@@ -131,7 +271,7 @@ func (m *Manager) engageStopProcedure() error {
 	<-m.shutdownCtx.Done()
 
 	if err := m.shutdownCtx.Err(); err != nil && !errors.Is(err, context.Canceled) {
-		return fmt.Errorf("not all components were shutdown completely within grace period(%s): %w", m.shutdownTimeout, err)
+		return m.shutdownTimeoutError(err)
 	}
 
 	return retErr
@@ -155,3 +295,17 @@ func (m *Manager) aggregateErrors(ch chan<- error) {
 	}
 	ch <- r
 }
+
+// uncancelled is a context.Context which carries the values and the
+// deadline of its parent but is never cancelled with it
+type uncancelled struct {
+	parent context.Context
+}
+
+func (u uncancelled) Deadline() (time.Time, bool) { return u.parent.Deadline() }
+
+func (uncancelled) Done() <-chan struct{} { return nil }
+
+func (uncancelled) Err() error { return nil }
+
+func (u uncancelled) Value(key any) any { return u.parent.Value(key) }
@@ -224,3 +224,144 @@ func (s *ManagerSuite) TestAddNewComponentAfterStart() {
 
 	s.NoError(<-errCh)
 }
+
+func (s *ManagerSuite) TestExitWhenAllDone() {
+	testcases := []struct {
+		name       string
+		options    []Option
+		components []Component
+		wantErr    assert.ErrorAssertionFunc
+		wantReturn bool
+	}{
+		{
+			name:       "WithZeroComponents",
+			options:    []Option{ExitWhenAllDone(true)},
+			wantErr:    assert.NoError,
+			wantReturn: true,
+		},
+		{
+			name:    "AllComponentsReturn",
+			options: []Option{ExitWhenAllDone(true)},
+			wantErr: assert.NoError,
+			components: []Component{
+				ComponentFunc(func(ctx context.Context) error { return nil }),
+				ComponentFunc(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				}),
+			},
+			wantReturn: true,
+		},
+		{
+			name:    "OneComponentKeepsRunning",
+			options: []Option{ExitWhenAllDone(true)},
+			wantErr: assert.NoError,
+			components: []Component{
+				ComponentFunc(func(ctx context.Context) error { return nil }),
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}),
+			},
+		},
+		{
+			name:    "WithoutOption",
+			wantErr: assert.NoError,
+			components: []Component{
+				ComponentFunc(func(ctx context.Context) error { return nil }),
+			},
+		},
+		{
+			name:    "PrimaryComponentReturns",
+			wantErr: assert.NoError,
+			components: []Component{
+				Primary(ComponentFunc(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				})),
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}),
+			},
+			wantReturn: true,
+		},
+		{
+			name:    "PrimaryComponentFails",
+			wantErr: assert.Error,
+			components: []Component{
+				Primary(ComponentFunc(func(ctx context.Context) error {
+					return errors.New("job error")
+				})),
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}),
+			},
+			wantReturn: true,
+		},
+		{
+			name:    "NotAllPrimaryComponentsReturn",
+			options: []Option{ExitWhenAllDone(true)},
+			wantErr: assert.NoError,
+			components: []Component{
+				Primary(ComponentFunc(func(ctx context.Context) error { return nil })),
+				Primary(ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				})),
+			},
+		},
+	}
+
+	for _, t := range testcases {
+		s.Run(t.name, func() {
+			m := NewManager(t.options...)
+
+			for _, c := range t.components {
+				s.NoError(m.Add(c))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- m.Run(ctx)
+			}()
+
+			select {
+			case err := <-errCh:
+				s.True(t.wantReturn, "Run returned before context was cancelled")
+				t.wantErr(s.T(), err)
+			case <-time.After(300 * time.Millisecond):
+				s.False(t.wantReturn, "Run did not return after components were done")
+				cancel()
+				t.wantErr(s.T(), <-errCh)
+			}
+		})
+	}
+}
+
+func (s *ManagerSuite) TestComponentContextDeadline() {
+	deadline := time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	var (
+		got time.Time
+		ok  bool
+	)
+
+	m := NewManager(ExitWhenAllDone(true))
+	s.NoError(m.Add(ComponentFunc(func(ctx context.Context) error {
+		got, ok = ctx.Deadline()
+
+		return nil
+	})))
+	s.NoError(m.Run(ctx))
+
+	s.True(ok)
+	s.Equal(deadline, got)
+}
@@ -0,0 +1,204 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware decorates a Component, example: to log or retry it. It should return
+// a Component created with Decorate, so that the optional interfaces of the decorated
+// Component, such as Reloadable, are still found by Manager.
+type Middleware func(Component) Component
+
+// Wrap decorates c with the middlewares, the first one is the outermost
+func Wrap(c Component, mws ...Middleware) Component {
+	for i := len(mws) - 1; i >= 0; i-- {
+		c = mws[i](c)
+	}
+
+	return c
+}
+
+// Decorate returns a Component which runs run in place of c, c is exposed through
+// Unwrap so that its optional interfaces and settings such as Named are preserved
+func Decorate(c Component, run func(ctx context.Context) error) Component {
+	return decoratedComponent{c: c, run: run}
+}
+
+type decoratedComponent struct {
+	c   Component
+	run func(ctx context.Context) error
+}
+
+func (d decoratedComponent) Run(ctx context.Context) error { return d.run(ctx) }
+
+// Unwrap returns the underlying Component
+func (d decoratedComponent) Unwrap() Component { return d.c }
+
+// Logging logs when the Component starts and returns, with the duration it ran for
+// and its error. When logger is nil, the Logger of the Component is used, otherwise
+// the Component is identified by the name given with Named.
+func Logging(logger *slog.Logger) Middleware {
+	return func(c Component) Component {
+		l := logger
+		if n, ok := find[namedComponent](c); ok && l != nil {
+			l = l.With("component", n.name)
+		}
+
+		return Decorate(c, func(ctx context.Context) error {
+			l := l
+			if l == nil {
+				l = Logger(ctx)
+			}
+
+			start := time.Now()
+
+			l.InfoContext(ctx, "component started")
+
+			err := c.Run(ctx)
+
+			if err != nil && !errors.Is(err, context.Canceled) {
+				l.ErrorContext(ctx, "component failed", "duration", time.Since(start), "error", err)
+			} else {
+				l.InfoContext(ctx, "component stopped", "duration", time.Since(start))
+			}
+
+			return err
+		})
+	}
+}
+
+// PanicError is returned by a Component decorated with Recover when it panics
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the goroutine which panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Recover turns a panic of the Component into a *PanicError
+func Recover() Middleware {
+	return func(c Component) Component {
+		return Decorate(c, func(ctx context.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+
+			return c.Run(ctx)
+		})
+	}
+}
+
+// Timeout limits the time the Component runs for, its context is closed after d.
+// It returns an error wrapping context.DeadlineExceeded when the limit is reached.
+func Timeout(d time.Duration) Middleware {
+	return func(c Component) Component {
+		return Decorate(c, func(ctx context.Context) error {
+			tCtx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			err := c.Run(tCtx)
+
+			if ctx.Err() == nil && errors.Is(tCtx.Err(), context.DeadlineExceeded) {
+				return errors.Join(fmt.Errorf("timeout after %s: %w", d, context.DeadlineExceeded), err)
+			}
+
+			return err
+		})
+	}
+}
+
+// RetryOptions holds options for Retry
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of runs, zero retries indefinitely
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, defaults to 100ms.
+	// It's doubled after every failed run.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between runs, defaults to 30 seconds
+	MaxBackoff time.Duration
+	// OnRetry is called with the error of a failed run, before the next attempt
+	OnRetry func(attempt int, err error)
+}
+
+// Retry runs the Component again with an exponential backoff when it returns an error,
+// until it returns without an error, the context is closed or MaxAttempts is reached
+func Retry(opts RetryOptions) Middleware {
+	initial := opts.InitialBackoff
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+
+	maxBackoff := opts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	return func(c Component) Component {
+		return Decorate(c, func(ctx context.Context) error {
+			backoff := initial
+
+			for attempt := 1; ; attempt++ {
+				err := c.Run(ctx)
+				if err == nil || ctx.Err() != nil || attempt == opts.MaxAttempts {
+					return err
+				}
+
+				if opts.OnRetry != nil {
+					opts.OnRetry(attempt, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(backoff):
+				}
+
+				backoff = min(2*backoff, maxBackoff)
+			}
+		})
+	}
+}
+
+// Delay starts the Component after d, it returns without running
+// the Component when the context is closed before that
+func Delay(d time.Duration) Middleware {
+	return func(c Component) Component {
+		return Decorate(c, func(ctx context.Context) error {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-timer.C:
+			}
+
+			return c.Run(ctx)
+		})
+	}
+}
+
+// Metrics calls observe with the duration the Component ran for and its error,
+// example: to record a histogram of run durations
+func Metrics(observe func(duration time.Duration, err error)) Middleware {
+	return func(c Component) Component {
+		return Decorate(c, func(ctx context.Context) error {
+			start := time.Now()
+
+			err := c.Run(ctx)
+
+			observe(time.Since(start), err)
+
+			return err
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package xrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapOrder(t *testing.T) {
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(c Component) Component {
+			return Decorate(c, func(ctx context.Context) error {
+				order = append(order, name)
+				return c.Run(ctx)
+			})
+		}
+	}
+
+	c := Wrap(ComponentFunc(func(ctx context.Context) error {
+		order = append(order, "component")
+		return nil
+	}), mw("outer"), mw("inner"))
+
+	assert.NoError(t, c.Run(context.Background()))
+	assert.Equal(t, []string{"outer", "inner", "component"}, order)
+}
+
+func TestWrapPreservesInterfaces(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+
+	c := Wrap(
+		Primary(Named("config", reloadable{name: "config", order: new([]string), mu: &sync.Mutex{}, reload: reloaded})),
+		Logging(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))),
+		Recover(),
+		Retry(RetryOptions{}),
+		Delay(0),
+		Timeout(time.Hour),
+		Metrics(func(time.Duration, error) {}),
+	)
+
+	assert.True(t, isPrimary(c))
+	assert.Equal(t, "config", nameOf(c, 0))
+
+	m := NewManager()
+	require.NoError(t, m.Add(c))
+	assert.NoError(t, m.Reload(context.Background()))
+	assert.Len(t, reloaded, 1)
+}
+
+func TestMiddlewares(t *testing.T) {
+	boom := errors.New("boom")
+
+	testcases := []struct {
+		name      string
+		mw        Middleware
+		run       func(ctx context.Context, attempt int) error
+		wantErr   string
+		wantErrIs error
+		wantRuns  int
+		minTime   time.Duration
+	}{
+		{
+			name: "RecoverPanic",
+			mw:   Recover(),
+			run: func(context.Context, int) error {
+				panic("oops")
+			},
+			wantErr:  "panic: oops",
+			wantRuns: 1,
+		},
+		{
+			name: "TimeoutReached",
+			mw:   Timeout(20 * time.Millisecond),
+			run: func(ctx context.Context, _ int) error {
+				<-ctx.Done()
+				return nil
+			},
+			wantErrIs: context.DeadlineExceeded,
+			wantRuns:  1,
+		},
+		{
+			name:     "TimeoutNotReached",
+			mw:       Timeout(time.Second),
+			run:      func(context.Context, int) error { return nil },
+			wantRuns: 1,
+		},
+		{
+			name: "RetryUntilSuccess",
+			mw:   Retry(RetryOptions{InitialBackoff: time.Millisecond}),
+			run: func(_ context.Context, attempt int) error {
+				if attempt < 3 {
+					return boom
+				}
+				return nil
+			},
+			wantRuns: 3,
+		},
+		{
+			name:      "RetryMaxAttempts",
+			mw:        Retry(RetryOptions{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+			run:       func(context.Context, int) error { return boom },
+			wantErrIs: boom,
+			wantRuns:  2,
+		},
+		{
+			name:     "Delay",
+			mw:       Delay(30 * time.Millisecond),
+			run:      func(context.Context, int) error { return nil },
+			wantRuns: 1,
+			minTime:  30 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := 0
+
+			c := tc.mw(ComponentFunc(func(ctx context.Context) error {
+				runs++
+				return tc.run(ctx, runs)
+			}))
+
+			start := time.Now()
+			err := c.Run(context.Background())
+
+			switch {
+			case tc.wantErr != "":
+				assert.EqualError(t, err, tc.wantErr)
+			case tc.wantErrIs != nil:
+				assert.ErrorIs(t, err, tc.wantErrIs)
+			default:
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantRuns, runs)
+			assert.GreaterOrEqual(t, time.Since(start), tc.minTime)
+		})
+	}
+}
+
+func TestDelayCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := Delay(time.Hour)(ComponentFunc(func(ctx context.Context) error {
+		t.Error("must not run")
+		return nil
+	}))
+
+	assert.NoError(t, c.Run(ctx))
+}
+
+func TestRecoverStack(t *testing.T) {
+	err := Recover()(ComponentFunc(func(ctx context.Context) error {
+		panic("oops")
+	})).Run(context.Background())
+
+	var pErr *PanicError
+	require.True(t, errors.As(err, &pErr))
+	assert.Equal(t, "oops", pErr.Value)
+	assert.Contains(t, string(pErr.Stack), "TestRecoverStack")
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	c := Wrap(Named("worker", ComponentFunc(func(ctx context.Context) error {
+		return errors.New("boom")
+	})), Logging(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	assert.Error(t, c.Run(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `msg="component started" component=worker`)
+	assert.Contains(t, lines[1], `msg="component failed" component=worker`)
+	assert.Contains(t, lines[1], "error=boom")
+}
+
+func TestMetrics(t *testing.T) {
+	var (
+		observed time.Duration
+		gotErr   error
+	)
+
+	boom := errors.New("boom")
+
+	c := Metrics(func(d time.Duration, err error) {
+		observed, gotErr = d, err
+	})(ComponentFunc(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return boom
+	}))
+
+	assert.ErrorIs(t, c.Run(context.Background()), boom)
+	assert.GreaterOrEqual(t, observed, 20*time.Millisecond)
+	assert.Equal(t, boom, gotErr)
+}
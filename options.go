@@ -1,6 +1,7 @@
 package xrun
 
 import (
+	"log/slog"
 	"time"
 )
 
@@ -18,3 +19,44 @@ type Option interface {
 type ShutdownTimeout time.Duration
 
 func (t ShutdownTimeout) apply(m *Manager) { m.shutdownTimeout = time.Duration(t) }
+
+// ExitWhenAllDone makes Manager.Run return once all the components
+// have returned on their own, instead of waiting for the context to be closed.
+// When Primary components are registered, only those are waited for.
+type ExitWhenAllDone bool
+
+func (e ExitWhenAllDone) apply(m *Manager) { m.exitWhenAllDone = bool(e) }
+
+// Name is the name of a Manager, which is the root of the Path of its components.
+// It defaults to "root", and is ignored when the Manager runs as a Component
+// of another Manager, where the name of the Component is used instead.
+type Name string
+
+func (n Name) apply(m *Manager) { m.name = string(n) }
+
+// WithLogger sets the logger from which Logger derives the loggers of the components,
+// it defaults to the one of the parent Manager or slog.Default
+func WithLogger(l *slog.Logger) Option {
+	return optionFunc(func(m *Manager) { m.logger = l })
+}
+
+type optionFunc func(*Manager)
+
+func (f optionFunc) apply(m *Manager) { f(m) }
+
+// Hooks are called by Manager on lifecycle events, all of them are optional.
+// Hooks may be called concurrently and must not block.
+type Hooks struct {
+	// OnReady is called once all the components are ready, see Ready
+	OnReady func()
+	// OnStopping is called when Manager starts stopping the components
+	OnStopping func()
+	// OnStateChange is called whenever a component changes its State
+	OnStateChange func(ComponentStatus)
+	// OnReload is called after Manager.Reload, with the error if any component failed to reload
+	OnReload func(error)
+	// OnMissedHeartbeat is called with the name of a Component which missed its heartbeat, see Watchdog
+	OnMissedHeartbeat func(name string)
+}
+
+func (h Hooks) apply(m *Manager) { m.hooks = append(m.hooks, h) }
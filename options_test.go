@@ -1,6 +1,8 @@
 package xrun
 
 import (
+	"io"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -13,3 +15,30 @@ func TestWithGracefulShutdownTimeout(t *testing.T) {
 	m := NewManager(ShutdownTimeout(expected))
 	assert.Equal(t, expected, m.shutdownTimeout)
 }
+
+func TestStartupTimeoutOption(t *testing.T) {
+	m := NewManager(StartupTimeout(time.Minute))
+	assert.Equal(t, time.Minute, m.startupTimeout)
+}
+
+func TestNameOption(t *testing.T) {
+	m := NewManager(Name("orders"))
+	assert.Equal(t, "orders", m.name)
+}
+
+func TestWithLogger(t *testing.T) {
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	m := NewManager(WithLogger(l))
+	assert.Equal(t, l, m.logger)
+}
+
+func TestExitWhenAllDone(t *testing.T) {
+	m := NewManager(ExitWhenAllDone(true))
+	assert.True(t, m.exitWhenAllDone)
+}
+
+func TestHooks(t *testing.T) {
+	m := NewManager(Hooks{}, Hooks{OnReady: func() {}})
+	assert.Len(t, m.hooks, 2)
+}
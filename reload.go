@@ -0,0 +1,98 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Reloadable can be implemented by a Component which is able to
+// apply new configuration without being restarted
+type Reloadable interface {
+	// Reload applies the new configuration, it is called while Run is in progress
+	Reload(ctx context.Context) error
+}
+
+// Reload calls Reload on all the Reloadable components in the order they were added,
+// including the ones of nested Managers, also when they are composed with All or run
+// by a ComponentFunc. Errors are aggregated, each prefixed with the name of the
+// Component which returned it, see Named.
+func (m *Manager) Reload(ctx context.Context) error {
+	m.statusMu.Lock()
+	halting := m.halting
+	m.statusMu.Unlock()
+
+	if halting {
+		return errors.New("can't reload components as stop procedure is already engaged")
+	}
+
+	m.mu.Lock()
+	components := append([]Component(nil), m.components...)
+	m.mu.Unlock()
+
+	var err error
+
+	for i, c := range components {
+		for _, r := range m.reloadables(c, i) {
+			if rErr := r.Reload(ctx); rErr != nil {
+				err = errors.Join(err, fmt.Errorf("%s: %w", nameOf(c, i), rErr))
+			}
+		}
+	}
+
+	for _, h := range m.hooks {
+		if h.OnReload != nil {
+			h.OnReload(err)
+		}
+	}
+
+	return err
+}
+
+// reloadables returns the Component if it's Reloadable, or else
+// the nested managers it runs, see initMetadata
+func (m *Manager) reloadables(c Component, i int) []Reloadable {
+	if r, ok := find[Reloadable](c); ok {
+		return []Reloadable{r}
+	}
+
+	var rs []Reloadable
+
+	for _, n := range m.nested(i) {
+		rs = append(rs, n)
+	}
+
+	return rs
+}
+
+// ReloadOnSignal makes Manager call Reload whenever one of the signals is received
+// while it's running, it defaults to SIGHUP. The result is reported to Hooks.OnReload.
+// upgrade.Upgrader also defaults to SIGHUP, set different signals when using both,
+// otherwise a single SIGHUP triggers both a reload and an upgrade.
+type ReloadOnSignal []os.Signal
+
+func (r ReloadOnSignal) apply(m *Manager) {
+	m.reloadSignals = r
+	if len(r) == 0 {
+		m.reloadSignals = []os.Signal{syscall.SIGHUP}
+	}
+}
+
+func (m *Manager) reloadOnSignal(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, m.reloadSignals...)
+
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			_ = m.Reload(ctx)
+		}
+	}
+}
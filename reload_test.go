@@ -0,0 +1,151 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadable struct {
+	err    error
+	order  *[]string
+	mu     *sync.Mutex
+	name   string
+	reload chan struct{}
+}
+
+func (r reloadable) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r reloadable) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.order = append(*r.order, r.name)
+	if r.reload != nil {
+		r.reload <- struct{}{}
+	}
+	return r.err
+}
+
+func TestManagerReload(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	nested := NewManager()
+	require.NoError(t, nested.Add(Named("db", reloadable{name: "db", order: &order, mu: &mu, err: errors.New("bad dsn")})))
+	require.NoError(t, nested.Add(reloadable{name: "cache", order: &order, mu: &mu}))
+
+	var reloadErr error
+
+	m := NewManager(Hooks{OnReload: func(err error) { reloadErr = err }})
+	require.NoError(t, m.Add(Named("http", reloadable{name: "http", order: &order, mu: &mu})))
+	require.NoError(t, m.Add(ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})))
+	require.NoError(t, m.Add(Primary(Named("store", nested))))
+	require.NoError(t, m.Add(Named("worker", reloadable{name: "worker", order: &order, mu: &mu, err: errors.New("bad queue")})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	err := m.Reload(context.Background())
+	assert.EqualError(t, err, "store: db: bad dsn\nworker: bad queue")
+	assert.Equal(t, err, reloadErr)
+	assert.Equal(t, []string{"http", "db", "cache", "worker"}, order)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+
+	assert.EqualError(t, m.Reload(context.Background()),
+		"can't reload components as stop procedure is already engaged")
+}
+
+func TestManagerReloadNested(t *testing.T) {
+	testcases := []struct {
+		name   string
+		nested func(c Component) Component
+	}{
+		{
+			name:   "All",
+			nested: func(c Component) Component { return All(NoTimeout, c) },
+		},
+		{
+			name: "ComponentFunc",
+			nested: func(c Component) Component {
+				m := NewManager()
+				_ = m.Add(c)
+
+				return ComponentFunc(func(ctx context.Context) error { return m.Run(ctx) })
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				order []string
+			)
+
+			m := NewManager()
+			require.NoError(t, m.Add(Named("store", tc.nested(
+				Named("db", reloadable{name: "db", order: &order, mu: &mu, err: errors.New("bad dsn")}),
+			))))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() { errCh <- m.Run(ctx) }()
+
+			require.Eventually(t, func() bool { return len(m.nested(0)) == 1 }, time.Second, 10*time.Millisecond)
+
+			assert.EqualError(t, m.Reload(context.Background()), "store: db: bad dsn")
+			assert.Equal(t, []string{"db"}, order)
+
+			cancel()
+			assert.NoError(t, <-errCh)
+		})
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	reloaded := make(chan struct{}, 1)
+
+	m := NewManager(ReloadOnSignal{})
+	assert.Equal(t, []os.Signal{syscall.SIGHUP}, m.reloadSignals)
+
+	require.NoError(t, m.Add(reloadable{name: "http", order: new([]string), mu: &sync.Mutex{}, reload: reloaded}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGHUP))
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("components were not reloaded on signal")
+	}
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
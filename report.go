@@ -0,0 +1,137 @@
+package xrun
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ShutdownReport describes how the components of a Manager were shutdown, see Manager.ShutdownReport
+type ShutdownReport struct {
+	// Timeout is the ShutdownTimeout of the Manager, zero when there is none
+	Timeout    Duration          `json:"timeout,omitempty"`
+	Components []ComponentReport `json:"components"`
+}
+
+// ComponentReport describes how a Component was shutdown
+type ComponentReport struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// SignalledAt is when the Component was asked to stop, it's zero
+	// when the Component returned before the shutdown started
+	SignalledAt time.Time `json:"signalledAt"`
+	// ReturnedAt is when Run returned, it's zero when the Component is still running
+	ReturnedAt time.Time `json:"returnedAt"`
+	// Duration is the time taken to return once signalled, or the time
+	// elapsed so far for a Component which is still running
+	Duration Duration `json:"duration"`
+	// Error is the error returned by the Component, if any
+	Error string `json:"error,omitempty"`
+	// Budget is the ShutdownTimeout of the Manager which ran the Component
+	Budget Duration `json:"budget,omitempty"`
+	// Exceeded reports whether Duration is over Budget
+	Exceeded bool `json:"exceeded"`
+}
+
+// ShutdownReport returns the time taken by each Component to return once signalled,
+// it's meant to be called after Run returns. Components of nested managers, added as
+// components or run inside a Component such as All, follow the Component running them.
+func (m *Manager) ShutdownReport() ShutdownReport {
+	r := ShutdownReport{Timeout: Duration(m.shutdownTimeout)}
+
+	m.statusMu.Lock()
+	states := append([]*componentState(nil), m.states...)
+	m.statusMu.Unlock()
+
+	for i, cs := range states {
+		if cs == nil {
+			continue
+		}
+
+		r.Components = append(r.Components, m.componentReport(cs, r.Timeout))
+
+		for _, nested := range m.nested(i) {
+			r.Components = append(r.Components, nested.ShutdownReport().Components...)
+		}
+	}
+
+	return r
+}
+
+func (m *Manager) componentReport(cs *componentState, budget Duration) ComponentReport {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	cr := ComponentReport{
+		Name:        cs.name,
+		Path:        cs.path,
+		SignalledAt: cs.signalledAt,
+		ReturnedAt:  cs.returnedAt,
+		Budget:      budget,
+	}
+
+	if cs.err != nil {
+		cr.Error = cs.err.Error()
+	}
+
+	switch {
+	case cs.signalledAt.IsZero():
+	case cs.returnedAt.IsZero():
+		cr.Duration = Duration(time.Since(cs.signalledAt))
+	default:
+		cr.Duration = Duration(cs.returnedAt.Sub(cs.signalledAt))
+	}
+
+	cr.Exceeded = budget > 0 && cr.Duration > budget
+
+	return cr
+}
+
+// WriteTable writes the report as a table, with a row per Component
+func (r ShutdownReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "COMPONENT\tSIGNALLED\tRETURNED\tDURATION\tBUDGET\tEXCEEDED\tERROR")
+
+	for _, c := range r.Components {
+		exceeded := "no"
+		if c.Exceeded {
+			exceeded = "yes"
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.Path,
+			formatTime(c.SignalledAt),
+			formatTime(c.ReturnedAt),
+			orDash(c.Duration > 0, time.Duration(c.Duration).String()),
+			orDash(c.Budget > 0, time.Duration(c.Budget).String()),
+			exceeded,
+			orDash(c.Error != "", strings.ReplaceAll(c.Error, "\n", "; ")),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// String returns the report as a table, see WriteTable
+func (r ShutdownReport) String() string {
+	var sb strings.Builder
+
+	_ = r.WriteTable(&sb)
+
+	return sb.String()
+}
+
+func formatTime(t time.Time) string {
+	return orDash(!t.IsZero(), t.Format("15:04:05.000"))
+}
+
+func orDash(ok bool, s string) string {
+	if !ok {
+		return "-"
+	}
+
+	return s
+}
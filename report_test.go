@@ -0,0 +1,159 @@
+package xrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownReport(t *testing.T) {
+	m := NewManager(ShutdownTimeout(time.Second))
+	require.NoError(t, m.Add(Named("fast", ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}))))
+	require.NoError(t, m.Add(Named("slow", ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+
+		return errors.New("flush failed")
+	}))))
+	require.NoError(t, m.Add(Named("job", ComponentFunc(func(ctx context.Context) error {
+		return errors.New("job failed")
+	}))))
+
+	assert.Error(t, m.Run(context.Background()))
+
+	r := m.ShutdownReport()
+	require.Len(t, r.Components, 3)
+	assert.Equal(t, Duration(time.Second), r.Timeout)
+
+	fast, slow, job := r.Components[0], r.Components[1], r.Components[2]
+
+	assert.Equal(t, "root/fast", fast.Path)
+	assert.False(t, fast.SignalledAt.IsZero())
+	assert.False(t, fast.ReturnedAt.Before(fast.SignalledAt))
+	assert.Empty(t, fast.Error)
+	assert.False(t, fast.Exceeded)
+
+	assert.Equal(t, "slow", slow.Name)
+	assert.GreaterOrEqual(t, slow.Duration, Duration(50*time.Millisecond))
+	assert.Equal(t, "flush failed", slow.Error)
+	assert.Equal(t, Duration(time.Second), slow.Budget)
+	assert.False(t, slow.Exceeded)
+
+	assert.True(t, job.SignalledAt.IsZero(), "returned before the shutdown")
+	assert.False(t, job.ReturnedAt.IsZero())
+	assert.Zero(t, job.Duration)
+	assert.Equal(t, "job failed", job.Error)
+}
+
+func TestShutdownReportExceeded(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := NewManager(ShutdownTimeout(20*time.Millisecond), Hooks{OnReady: cancel})
+	require.NoError(t, m.Add(Named("stuck", ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+		<-release
+
+		return nil
+	}))))
+
+	var tErr *ShutdownTimeoutError
+	require.ErrorAs(t, m.Run(ctx), &tErr)
+
+	r := m.ShutdownReport()
+	require.Len(t, r.Components, 1)
+
+	stuck := r.Components[0]
+	assert.False(t, stuck.SignalledAt.IsZero())
+	assert.True(t, stuck.ReturnedAt.IsZero(), "still running")
+	assert.GreaterOrEqual(t, stuck.Duration, Duration(20*time.Millisecond))
+	assert.True(t, stuck.Exceeded)
+}
+
+func TestShutdownReportNested(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	run := ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+
+		return nil
+	})
+
+	m := NewManager(Name("orders"), Hooks{OnReady: cancel})
+	require.NoError(t, m.Add(Named("api", All(time.Second, Named("http", run), Named("grpc", run)))))
+	require.NoError(t, m.Add(Named("worker", run)))
+
+	require.NoError(t, m.Run(ctx))
+
+	r := m.ShutdownReport()
+	require.Len(t, r.Components, 4)
+
+	var paths []string
+	for _, c := range r.Components {
+		paths = append(paths, c.Path)
+	}
+
+	assert.Equal(t, []string{"orders/api", "orders/api/http", "orders/api/grpc", "orders/worker"}, paths)
+	assert.Zero(t, r.Components[0].Budget)
+	assert.Equal(t, Duration(time.Second), r.Components[1].Budget)
+}
+
+func TestShutdownReportFormat(t *testing.T) {
+	signalled := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	r := ShutdownReport{
+		Timeout: Duration(time.Second),
+		Components: []ComponentReport{
+			{
+				Name:        "http",
+				Path:        "root/http",
+				SignalledAt: signalled,
+				ReturnedAt:  signalled.Add(1500 * time.Millisecond),
+				Duration:    Duration(1500 * time.Millisecond),
+				Error:       "a\nb",
+				Budget:      Duration(time.Second),
+				Exceeded:    true,
+			},
+			{
+				Name:       "job",
+				Path:       "root/job",
+				ReturnedAt: signalled,
+				Budget:     Duration(time.Second),
+			},
+		},
+	}
+
+	want := `COMPONENT  SIGNALLED     RETURNED      DURATION  BUDGET  EXCEEDED  ERROR
+root/http  15:04:05.000  15:04:06.500  1.5s      1s      yes       a; b
+root/job   -             15:04:05.000  -         1s      no        -
+`
+	assert.Equal(t, want, r.String())
+
+	b, err := json.Marshal(r.Components[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "http",
+		"path": "root/http",
+		"signalledAt": "2024-01-02T15:04:05Z",
+		"returnedAt": "2024-01-02T15:04:06.5Z",
+		"duration": "1.5s",
+		"error": "a\nb",
+		"budget": "1s",
+		"exceeded": true
+	}`, string(b))
+}
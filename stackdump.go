@@ -0,0 +1,124 @@
+package xrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"strings"
+	"time"
+)
+
+// componentLabel is the pprof label carrying the Path of the Component
+// which started a goroutine, it appears in goroutine dumps
+const componentLabel = "xrun.component"
+
+// StackDump makes Manager capture the stacks of all goroutines when the ShutdownTimeout
+// expires. Goroutines are labelled with the Path of the Component which started them,
+// and the dump lists the components which were still running.
+type StackDump struct {
+	// Writer receives the dump, when nil it's attached to the ShutdownTimeoutError
+	// returned by Manager.Run
+	Writer io.Writer
+	// Signals trigger a dump at any time while Manager runs, example: syscall.SIGQUIT.
+	// It's written to Writer, or os.Stderr when Writer is nil.
+	Signals []os.Signal
+}
+
+func (s StackDump) apply(m *Manager) { m.stackDump = &s }
+
+// ShutdownTimeoutError is returned by Manager.Run when
+// components are still running after the ShutdownTimeout
+type ShutdownTimeoutError struct {
+	// Timeout is the ShutdownTimeout which expired
+	Timeout time.Duration
+	// Components are the names of the components which were still running
+	Components []string
+	// Stacks is the goroutine dump, set when StackDump is used without a Writer
+	Stacks []byte
+
+	err error
+}
+
+func (e *ShutdownTimeoutError) Error() string {
+	msg := fmt.Sprintf("not all components were shutdown completely within grace period(%s)", e.Timeout)
+
+	if len(e.Components) > 0 {
+		msg += ", still running: " + strings.Join(e.Components, ", ")
+	}
+
+	return msg + ": " + e.err.Error()
+}
+
+func (e *ShutdownTimeoutError) Unwrap() error { return e.err }
+
+func (m *Manager) shutdownTimeoutError(err error) error {
+	running := m.running()
+
+	tErr := &ShutdownTimeoutError{Timeout: m.shutdownTimeout, Components: running, err: err}
+
+	if m.stackDump == nil {
+		return tErr
+	}
+
+	if m.stackDump.Writer != nil {
+		_ = dumpStacks(m.stackDump.Writer, running)
+
+		return tErr
+	}
+
+	var buf bytes.Buffer
+
+	_ = dumpStacks(&buf, running)
+	tErr.Stacks = buf.Bytes()
+
+	return tErr
+}
+
+// running returns the names of the components which have not returned yet
+func (m *Manager) running() []string {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	var names []string
+
+	for _, cs := range m.states {
+		if cs != nil && cs.state != StateStopped && cs.state != StateFailed {
+			names = append(names, cs.name)
+		}
+	}
+
+	return names
+}
+
+func (m *Manager) dumpOnSignal(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, m.stackDump.Signals...)
+
+	defer signal.Stop(sigCh)
+
+	w := m.stackDump.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			_ = dumpStacks(w, m.running())
+		}
+	}
+}
+
+func dumpStacks(w io.Writer, running []string) error {
+	if _, err := fmt.Fprintf(w, "xrun: components still running: %s\n\n", strings.Join(running, ", ")); err != nil {
+		return err
+	}
+
+	return pprof.Lookup("goroutine").WriteTo(w, 1)
+}
@@ -0,0 +1,135 @@
+package xrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackDump(t *testing.T) {
+	testcases := []struct {
+		name       string
+		dump       *StackDump
+		writer     *bytes.Buffer
+		wantStacks bool
+	}{
+		{
+			name: "WithoutStackDump",
+		},
+		{
+			name:       "AttachedToError",
+			dump:       &StackDump{},
+			wantStacks: true,
+		},
+		{
+			name:   "WrittenToWriter",
+			dump:   &StackDump{},
+			writer: &bytes.Buffer{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+
+			opts := []Option{ShutdownTimeout(50 * time.Millisecond)}
+
+			if tc.dump != nil {
+				if tc.writer != nil {
+					tc.dump.Writer = tc.writer
+				}
+
+				opts = append(opts, *tc.dump)
+			}
+
+			m := NewManager(opts...)
+
+			require.NoError(t, m.Add(Named("stuck", ComponentFunc(func(ctx context.Context) error {
+				<-release
+				return nil
+			}))))
+			require.NoError(t, m.Add(Named("graceful", ComponentFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := m.Run(ctx)
+
+			var tErr *ShutdownTimeoutError
+			require.True(t, errors.As(err, &tErr))
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Equal(t, []string{"stuck"}, tErr.Components)
+			assert.Contains(t, err.Error(), "still running: stuck")
+
+			if tc.wantStacks {
+				assert.Contains(t, string(tErr.Stacks), "components still running: stuck")
+				assert.Contains(t, string(tErr.Stacks), `"xrun.component":"root/stuck"`)
+			} else {
+				assert.Nil(t, tErr.Stacks)
+			}
+
+			if tc.writer != nil {
+				assert.Contains(t, tc.writer.String(), `"xrun.component":"root/stuck"`)
+			}
+		})
+	}
+}
+
+func TestStackDumpOnSignal(t *testing.T) {
+	w := &syncBuffer{}
+
+	m := NewManager(StackDump{Writer: w, Signals: []os.Signal{syscall.SIGHUP}})
+
+	require.NoError(t, m.Add(Named("worker", ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		return bytes.Contains(w.Bytes(), []byte(`"xrun.component":"root/worker"`))
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
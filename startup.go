@@ -0,0 +1,131 @@
+package xrun
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotReady is returned by Manager.Run when a Component has not
+// signalled readiness with Ready within its startup timeout
+var ErrNotReady = errors.New("not ready")
+
+// StartupTimeout makes Manager.Run fail with ErrNotReady when components have not
+// signalled readiness with Ready within the timeout, the components are then shutdown
+// gracefully. It can be overridden for a Component with ReadyWithin.
+type StartupTimeout time.Duration
+
+func (t StartupTimeout) apply(m *Manager) { m.startupTimeout = time.Duration(t) }
+
+// ReadyWithin sets the startup timeout of a Component, overriding StartupTimeout
+func ReadyWithin(timeout time.Duration, c Component) Component {
+	return readyWithinComponent{Component: c, timeout: timeout}
+}
+
+type readyWithinComponent struct {
+	Component
+	timeout time.Duration
+}
+
+// Unwrap returns the underlying Component
+func (r readyWithinComponent) Unwrap() Component { return r.Component }
+
+// watchStartup fails the components which are not ready within their
+// startup timeout, it must be called with mu held once the states are set
+func (m *Manager) watchStartup() {
+	timeouts := make(map[*componentState]time.Duration)
+
+	for i, c := range m.components {
+		if c == nil {
+			continue
+		}
+
+		timeout := m.startupTimeout
+		if r, ok := find[readyWithinComponent](c); ok {
+			timeout = r.timeout
+		}
+
+		if timeout > 0 {
+			timeouts[m.states[i]] = timeout
+		}
+	}
+
+	if len(timeouts) == 0 {
+		return
+	}
+
+	ctx := m.internalCtx
+	start := time.Now()
+
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		for len(timeouts) > 0 {
+			var next time.Duration
+
+			for _, timeout := range timeouts {
+				if next == 0 || timeout < next {
+					next = timeout
+				}
+			}
+
+			timer := time.NewTimer(time.Until(start.Add(next)))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return
+			case <-timer.C:
+			}
+
+			if err := m.failNotReady(timeouts, time.Since(start)); err != nil {
+				m.errChan <- err
+
+				return
+			}
+		}
+	}()
+}
+
+// failNotReady moves the components which are still starting after their timeout
+// to StateFailed, the components which are no longer starting are not watched anymore
+func (m *Manager) failNotReady(timeouts map[*componentState]time.Duration, elapsed time.Duration) error {
+	var (
+		err    error
+		failed []*componentState
+	)
+
+	m.statusMu.Lock()
+
+	if m.halting {
+		m.statusMu.Unlock()
+
+		return nil
+	}
+
+	for _, cs := range m.states {
+		timeout, ok := timeouts[cs]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case cs.state != StateStarting:
+			delete(timeouts, cs)
+		case timeout <= elapsed:
+			failed = append(failed, cs)
+			err = errors.Join(err, fmt.Errorf("%s: %w within %s", cs.name, ErrNotReady, timeout))
+		}
+	}
+
+	m.statusMu.Unlock()
+
+	for _, cs := range failed {
+		m.setState(cs, StateFailed)
+	}
+
+	return err
+}
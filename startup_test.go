@@ -0,0 +1,104 @@
+package xrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupTimeout(t *testing.T) {
+	ready := func(delay time.Duration) Component {
+		return ComponentFunc(func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(delay):
+			}
+
+			Ready(ctx)
+			<-ctx.Done()
+
+			return nil
+		})
+	}
+
+	testcases := []struct {
+		name       string
+		options    []Option
+		components []Component
+		wantErr    string
+	}{
+		{
+			name:    "AllReadyInTime",
+			options: []Option{StartupTimeout(100 * time.Millisecond)},
+			components: []Component{
+				Named("http", ready(10*time.Millisecond)),
+				Named("db", ready(20*time.Millisecond)),
+			},
+		},
+		{
+			name:    "GlobalTimeout",
+			options: []Option{StartupTimeout(50 * time.Millisecond)},
+			components: []Component{
+				Named("http", ready(10*time.Millisecond)),
+				Named("db", ready(time.Second)),
+				Named("cache", ready(time.Second)),
+			},
+			wantErr: "db: not ready within 50ms\ncache: not ready within 50ms",
+		},
+		{
+			name:    "PerComponentTimeout",
+			options: []Option{StartupTimeout(50 * time.Millisecond)},
+			components: []Component{
+				ReadyWithin(200*time.Millisecond, Named("migrations", ready(100*time.Millisecond))),
+				ReadyWithin(20*time.Millisecond, Named("http", ready(time.Second))),
+			},
+			wantErr: "http: not ready within 20ms",
+		},
+		{
+			name: "OnlyPerComponentTimeout",
+			components: []Component{
+				Named("worker", ready(time.Second)),
+				ReadyWithin(20*time.Millisecond, Named("http", ready(time.Second))),
+			},
+			wantErr: "http: not ready within 20ms",
+		},
+		{
+			name:    "ReturnedComponentIsNotWatched",
+			options: []Option{StartupTimeout(20 * time.Millisecond)},
+			components: []Component{
+				Named("migrations", ComponentFunc(func(ctx context.Context) error { return nil })),
+				Named("http", ready(10*time.Millisecond)),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewManager(tc.options...)
+
+			for _, c := range tc.components {
+				require.NoError(t, m.Add(c))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := m.Run(ctx)
+
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.EqualError(t, err, tc.wantErr)
+			assert.ErrorIs(t, err, ErrNotReady)
+			assert.Less(t, time.Since(start), 250*time.Millisecond)
+		})
+	}
+}
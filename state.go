@@ -0,0 +1,193 @@
+package xrun
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// State is the lifecycle state of a Component run by a Manager
+type State int
+
+const (
+	// StateStarting is the state of a Component which has been started
+	// but has not signalled readiness with Ready yet
+	StateStarting State = iota
+	// StateReady is the state of a Component which has signalled readiness with Ready
+	StateReady
+	// StateStopping is the state of a Component which has been asked to stop
+	StateStopping
+	// StateStopped is the state of a Component which has returned without an error
+	StateStopped
+	// StateFailed is the state of a Component which has returned with an error
+	StateFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "starting"
+	case StateReady:
+		return "ready"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ComponentStatus is the State of a named Component
+type ComponentStatus struct {
+	Name  string
+	State State
+}
+
+// Ready signals that the Component running with ctx is ready, example: an HTTP server
+// which is accepting connections. It must be called with the context passed to Run,
+// calling it with any other context is a no-op. A Manager is ready, and signals its own
+// readiness when it's run as a Component, once all of its components are ready or have
+// returned without an error.
+func Ready(ctx context.Context) {
+	if cs, ok := stateFrom(ctx); ok {
+		cs.m.setState(cs, StateReady)
+	}
+}
+
+type componentStateKey struct{}
+
+type componentState struct {
+	m      *Manager
+	name   string
+	path   string
+	logger *slog.Logger
+	state  State
+
+	// nested are the managers run by the Component, guarded by statusMu
+	nested []*Manager
+
+	// signalledAt, returnedAt and err are guarded by statusMu, see ShutdownReport
+	signalledAt time.Time
+	returnedAt  time.Time
+	err         error
+
+	beat atomic.Int64
+}
+
+func (cs *componentState) heartbeat() { cs.beat.Store(time.Now().UnixNano()) }
+
+func (cs *componentState) lastHeartbeat() time.Time { return time.Unix(0, cs.beat.Load()) }
+
+func (m *Manager) setState(cs *componentState, s State) {
+	m.statusMu.Lock()
+
+	if !cs.state.canTransitionTo(s) {
+		m.statusMu.Unlock()
+
+		return
+	}
+
+	cs.state = s
+	ready := m.becameReady()
+
+	m.statusMu.Unlock()
+
+	m.stateChanged(ComponentStatus{Name: cs.name, State: s})
+
+	if ready {
+		m.ready()
+	}
+}
+
+// returned records when and how the Component of cs returned
+func (m *Manager) returned(cs *componentState, err error) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	cs.returnedAt = time.Now()
+	cs.err = err
+}
+
+// becameReady reports whether all the components have become ready,
+// it must be called with statusMu held
+func (m *Manager) becameReady() bool {
+	if m.readyFired || m.halting {
+		return false
+	}
+
+	for _, cs := range m.states {
+		if cs != nil && cs.state != StateReady && cs.state != StateStopped {
+			return false
+		}
+	}
+
+	m.readyFired = true
+
+	return true
+}
+
+func (s State) canTransitionTo(next State) bool {
+	switch next {
+	case StateReady:
+		return s == StateStarting
+	case StateStopping:
+		return s == StateStarting || s == StateReady
+	case StateStopped, StateFailed:
+		return s != StateStopped && s != StateFailed
+	default:
+		return false
+	}
+}
+
+func (m *Manager) stateChanged(cs ComponentStatus) {
+	for _, h := range m.hooks {
+		if h.OnStateChange != nil {
+			h.OnStateChange(cs)
+		}
+	}
+}
+
+func (m *Manager) ready() {
+	for _, h := range m.hooks {
+		if h.OnReady != nil {
+			h.OnReady()
+		}
+	}
+
+	Ready(m.runCtx)
+}
+
+// halt moves all the running components to StateStopping
+func (m *Manager) halt() {
+	m.statusMu.Lock()
+
+	m.halting = true
+
+	var changed []ComponentStatus
+
+	now := time.Now()
+
+	for _, cs := range m.states {
+		if cs != nil && cs.state.canTransitionTo(StateStopping) {
+			cs.state = StateStopping
+			cs.signalledAt = now
+			changed = append(changed, ComponentStatus{Name: cs.name, State: StateStopping})
+		}
+	}
+
+	m.statusMu.Unlock()
+
+	for _, h := range m.hooks {
+		if h.OnStopping != nil {
+			h.OnStopping()
+		}
+	}
+
+	for _, cs := range changed {
+		m.stateChanged(cs)
+	}
+}
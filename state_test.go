@@ -0,0 +1,174 @@
+package xrun
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StateSuite struct {
+	suite.Suite
+}
+
+func TestStateSuite(t *testing.T) {
+	suite.Run(t, new(StateSuite))
+}
+
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+	ready  chan struct{}
+}
+
+func newHookRecorder() *hookRecorder {
+	return &hookRecorder{ready: make(chan struct{})}
+}
+
+func (r *hookRecorder) hooks() Hooks {
+	return Hooks{
+		OnReady: func() {
+			r.record("ready")
+			close(r.ready)
+		},
+		OnStopping: func() { r.record("stopping") },
+		OnStateChange: func(cs ComponentStatus) {
+			r.record(cs.Name + ":" + cs.State.String())
+		},
+	}
+}
+
+func (r *hookRecorder) record(e string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *hookRecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func (s *StateSuite) TestHooks() {
+	r := newHookRecorder()
+	m := NewManager(r.hooks())
+
+	release := make(chan struct{})
+
+	s.NoError(m.Add(Named("api", ComponentFunc(func(ctx context.Context) error {
+		<-release
+		Ready(ctx)
+		<-ctx.Done()
+		return nil
+	}))))
+	s.NoError(m.Add(ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+		return errors.New("shutdown error")
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	s.Eventually(func() bool { return len(r.recorded()) == 3 }, time.Second, 10*time.Millisecond)
+	s.Equal([]string{"api:starting", "component-2:starting", "component-2:ready"}, r.recorded())
+
+	close(release)
+	<-r.ready
+	cancel()
+
+	s.EqualError(<-errCh, "shutdown error")
+	s.Equal([]string{
+		"api:starting", "component-2:starting", "component-2:ready",
+		"api:ready", "ready",
+		"stopping", "api:stopping", "component-2:stopping",
+	}, r.recorded()[:8])
+	s.ElementsMatch([]string{"api:stopped", "component-2:failed"}, r.recorded()[8:])
+}
+
+func (s *StateSuite) TestNestedManagerReadiness() {
+	r := newHookRecorder()
+	m := NewManager(r.hooks())
+
+	child := NewManager()
+	s.NoError(child.Add(ComponentFunc(func(ctx context.Context) error {
+		Ready(ctx)
+		<-ctx.Done()
+		return nil
+	})))
+	s.NoError(child.Add(ComponentFunc(func(ctx context.Context) error {
+		return nil
+	})))
+
+	s.NoError(m.Add(Named("child", child)))
+	s.NoError(m.Add(Named("all", All(NoTimeout))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	select {
+	case <-r.ready:
+	case <-time.After(time.Second):
+		s.Fail("manager did not become ready")
+	}
+
+	s.Contains(r.recorded(), "child:ready")
+	s.Contains(r.recorded(), "all:ready")
+
+	cancel()
+	s.NoError(<-errCh)
+}
+
+func (s *StateSuite) TestReadyOutsideManager() {
+	s.NotPanics(func() { Ready(context.Background()) })
+}
+
+func (s *StateSuite) TestReadyAfterStopping() {
+	r := newHookRecorder()
+	m := NewManager(r.hooks())
+
+	s.NoError(m.Add(Named("slow", ComponentFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		Ready(ctx)
+		return nil
+	}))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	s.Eventually(func() bool { return len(r.recorded()) == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	s.NoError(<-errCh)
+	s.Equal([]string{"slow:starting", "stopping", "slow:stopping", "slow:stopped"}, r.recorded())
+}
+
+func TestStateString(t *testing.T) {
+	for s, want := range map[State]string{
+		StateStarting: "starting",
+		StateReady:    "ready",
+		StateStopping: "stopping",
+		StateStopped:  "stopped",
+		StateFailed:   "failed",
+		State(-1):     "unknown",
+	} {
+		assert.Equal(t, want, s.String())
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	c := ComponentFunc(func(ctx context.Context) error { return nil })
+
+	assert.Equal(t, "component-3", nameOf(c, 2))
+	assert.Equal(t, "worker", nameOf(Named("worker", c), 2))
+	assert.Equal(t, "worker", nameOf(Primary(Named("worker", c)), 0))
+	assert.True(t, isPrimary(Named("worker", Primary(c))))
+}
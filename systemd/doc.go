@@ -0,0 +1,33 @@
+/*
+Package systemd integrates a Manager with systemd, it supports service
+notifications for units of Type=notify and socket activation.
+
+	package main
+
+	import (
+		"context"
+		"net/http"
+		"os"
+		"os/signal"
+
+		"github.com/gojekfarm/xrun"
+		"github.com/gojekfarm/xrun/component"
+		"github.com/gojekfarm/xrun/systemd"
+	)
+
+	func main() {
+		n := systemd.NewNotifier()
+		m := xrun.NewManager(n.Hooks())
+
+		_ = m.Add(xrun.Named("systemd", n))
+		_ = m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{Server: &http.Server{}})))
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := m.Run(ctx); err != nil {
+			os.Exit(1)
+		}
+	}
+*/
+package systemd
@@ -0,0 +1,50 @@
+package systemd_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+	"github.com/gojekfarm/xrun/systemd"
+)
+
+func ExampleNotifier() {
+	n := systemd.NewNotifier()
+	m := xrun.NewManager(n.Hooks())
+
+	// n sends watchdog notifications when it's run along with the other components
+	if err := m.Add(xrun.Named("systemd", n)); err != nil {
+		panic(err)
+	}
+
+	if err := m.Add(xrun.Named("http", component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{},
+	}))); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func ExampleListener() {
+	// the socket unit has FileDescriptorName=http
+	c := component.HTTPServer(component.HTTPServerOptions{
+		Server:      &http.Server{},
+		NewListener: systemd.Listener("http"),
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := c.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
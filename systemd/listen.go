@@ -0,0 +1,94 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gojekfarm/xrun/component"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd, see sd_listen_fds(3)
+const listenFDsStart = 3
+
+var (
+	activationOnce sync.Once
+	activated      map[string][]net.Listener
+	activationErr  error
+)
+
+// Listeners returns the listeners passed by systemd socket activation, keyed by
+// the names set with FileDescriptorName= in the socket unit, unnamed ones are keyed
+// as "unknown". The LISTEN_* environment variables are unset, so that they are
+// not inherited by child processes, and the listeners are returned on later calls.
+func Listeners() (map[string][]net.Listener, error) {
+	activationOnce.Do(func() {
+		activated, activationErr = listeners(os.Getpid())
+
+		for _, env := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			_ = os.Unsetenv(env)
+		}
+	})
+
+	return activated, activationErr
+}
+
+// Listener returns a component.ListenerFunc which returns the listener
+// passed by systemd socket activation with the given name. It can be used as
+// NewListener of component.HTTPServer and the gRPC Server, so that the socket
+// stays open and accepts connections while the process restarts.
+func Listener(name string) component.ListenerFunc {
+	return func() (net.Listener, error) {
+		ls, err := Listeners()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ls[name]) == 0 {
+			return nil, fmt.Errorf("systemd: no listener named %q was passed by socket activation", name)
+		}
+
+		return ls[name][0], nil
+	}
+}
+
+func listeners(pid int) (map[string][]net.Listener, error) {
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(pid) {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+
+	var names []string
+	if v := os.Getenv("LISTEN_FDNAMES"); v != "" {
+		names = strings.Split(v, ":")
+	}
+
+	ls := make(map[string][]net.Listener, n)
+
+	for i := 0; i < n; i++ {
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		f := os.NewFile(uintptr(listenFDsStart+i), name)
+
+		l, err := net.FileListener(f)
+		_ = f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("systemd: file descriptor %d (%s) is not a listener: %w", listenFDsStart+i, name, err)
+		}
+
+		ls[name] = append(ls[name], l)
+	}
+
+	return ls, nil
+}
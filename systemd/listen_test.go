@@ -0,0 +1,104 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+)
+
+// TestListenerHelperProcess is run as a child process by TestSocketActivation,
+// the listeners are inherited as file descriptors like systemd would pass them
+func TestListenerHelperProcess(t *testing.T) {
+	if os.Getenv("XRUN_SYSTEMD_HELPER") != "1" {
+		t.Skip("helper process for TestSocketActivation")
+	}
+
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/name", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Context().Value(http.LocalAddrContextKey).(net.Addr).String()))
+	})
+
+	_, missingErr := Listener("missing")()
+
+	m := xrun.NewManager()
+	_ = m.Add(component.HTTPServer(component.HTTPServerOptions{
+		Server:      &http.Server{Handler: mux},
+		NewListener: Listener("http"),
+	}))
+	_ = m.Add(component.HTTPServer(component.HTTPServerOptions{
+		Server:      &http.Server{Handler: mux},
+		NewListener: Listener("unknown"),
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fmt.Println("env:", os.Getenv("LISTEN_FDS") == "", "missing:", missingErr)
+	_ = m.Run(ctx)
+}
+
+func TestSocketActivation(t *testing.T) {
+	httpL, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer httpL.Close()
+
+	otherL, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer otherL.Close()
+
+	httpF, err := httpL.(*net.TCPListener).File()
+	require.NoError(t, err)
+	otherF, err := otherL.(*net.TCPListener).File()
+	require.NoError(t, err)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestListenerHelperProcess$", "-test.v")
+	cmd.Env = append(os.Environ(), "XRUN_SYSTEMD_HELPER=1", "LISTEN_FDS=2", "LISTEN_FDNAMES=http:")
+	cmd.ExtraFiles = []*os.File{httpF, otherF}
+
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	// connections are accepted by the child through the inherited listeners
+	for _, l := range []net.Listener{httpL, otherL} {
+		resp, err := http.Get("http://" + l.Addr().String() + "/name")
+		require.NoError(t, err)
+
+		d, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, l.Addr().String(), string(d))
+	}
+
+	require.NoError(t, cmd.Process.Kill())
+	out, _ := io.ReadAll(stdout)
+	assert.Contains(t, string(out), `env: true missing: systemd: no listener named "missing" was passed by socket activation`)
+}
+
+func TestListenersWithoutActivation(t *testing.T) {
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+
+	ls, err := listeners(os.Getpid())
+	assert.NoError(t, err)
+	assert.Nil(t, ls)
+}
@@ -0,0 +1,163 @@
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+)
+
+// Notifier sends service status notifications to systemd, see sd_notify(3).
+// Its Hooks send READY=1 once all the components of the Manager are ready,
+// STOPPING=1 when the Manager starts stopping and STATUS= with the component
+// states. When added to the Manager as a Component, it also sends WATCHDOG=1
+// at half of WATCHDOG_USEC while all the components are healthy.
+// All notifications are skipped when NOTIFY_SOCKET is not set.
+type Notifier struct {
+	socket   string
+	watchdog time.Duration
+
+	mu       sync.Mutex
+	names    []string
+	states   map[string]xrun.State
+	ready    bool
+	stopping bool
+}
+
+// NewNotifier creates a Notifier from the NOTIFY_SOCKET, WATCHDOG_USEC
+// and WATCHDOG_PID environment variables set by systemd
+func NewNotifier() *Notifier {
+	n := &Notifier{
+		socket: os.Getenv("NOTIFY_SOCKET"),
+		states: make(map[string]xrun.State),
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return n
+	}
+
+	if usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 {
+		n.watchdog = time.Duration(usec) * time.Microsecond
+	}
+
+	return n
+}
+
+// Hooks returns the xrun.Hooks which notify systemd of the Manager lifecycle
+func (n *Notifier) Hooks() xrun.Hooks {
+	return xrun.Hooks{
+		OnReady:       n.onReady,
+		OnStopping:    n.onStopping,
+		OnStateChange: n.onStateChange,
+	}
+}
+
+// Run sends watchdog notifications while all the components are healthy,
+// it blocks until the context is closed
+func (n *Notifier) Run(ctx context.Context) error {
+	xrun.Ready(ctx)
+
+	if n.watchdog <= 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	ticker := time.NewTicker(n.watchdog / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if n.healthy() {
+				_ = n.Notify("WATCHDOG=1")
+			}
+		}
+	}
+}
+
+// Notify sends the newline separated assignments in state to systemd,
+// example: "READY=1" or "STATUS=migrating database"
+func (n *Notifier) Notify(state string) error {
+	if n.socket == "" {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+
+	return err
+}
+
+func (n *Notifier) onReady() {
+	n.mu.Lock()
+	n.ready = true
+	status := n.status()
+	n.mu.Unlock()
+
+	_ = n.Notify("READY=1\n" + status)
+}
+
+func (n *Notifier) onStopping() {
+	n.mu.Lock()
+	n.stopping = true
+	n.mu.Unlock()
+
+	_ = n.Notify("STOPPING=1")
+}
+
+func (n *Notifier) onStateChange(cs xrun.ComponentStatus) {
+	n.mu.Lock()
+
+	if _, ok := n.states[cs.Name]; !ok {
+		n.names = append(n.names, cs.Name)
+	}
+
+	n.states[cs.Name] = cs.State
+	status := n.status()
+
+	n.mu.Unlock()
+
+	_ = n.Notify(status)
+}
+
+// status must be called with mu held
+func (n *Notifier) status() string {
+	parts := make([]string, 0, len(n.names))
+
+	for _, name := range n.names {
+		parts = append(parts, name+": "+n.states[name].String())
+	}
+
+	return "STATUS=" + strings.Join(parts, ", ")
+}
+
+func (n *Notifier) healthy() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.ready || n.stopping {
+		return false
+	}
+
+	for _, s := range n.states {
+		if s != xrun.StateReady && s != xrun.StateStopped {
+			return false
+		}
+	}
+
+	return true
+}
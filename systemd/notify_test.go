@@ -0,0 +1,134 @@
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gojekfarm/xrun"
+)
+
+func TestNotifier(t *testing.T) {
+	msgs := fakeNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "100000")
+
+	n := NewNotifier()
+	m := xrun.NewManager(n.Hooks())
+
+	release := make(chan struct{})
+
+	require.NoError(t, m.Add(xrun.Named("systemd", n)))
+	require.NoError(t, m.Add(xrun.Named("api", xrun.ComponentFunc(func(ctx context.Context) error {
+		<-release
+		xrun.Ready(ctx)
+		<-ctx.Done()
+		return nil
+	}))))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	waitFor(t, msgs, "STATUS=systemd: ready, api: starting")
+
+	select {
+	case msg := <-msgs:
+		t.Fatalf("unexpected notification before components are ready: %q", msg)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	close(release)
+	waitFor(t, msgs, "STATUS=systemd: ready, api: ready")
+	waitFor(t, msgs, "READY=1\nSTATUS=systemd: ready, api: ready")
+	waitFor(t, msgs, "WATCHDOG=1")
+	waitFor(t, msgs, "WATCHDOG=1")
+
+	cancel()
+	waitFor(t, msgs, "STOPPING=1")
+	assert.NoError(t, <-errCh)
+}
+
+func TestNotifierWithoutSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	assert.NoError(t, NewNotifier().Notify("READY=1"))
+}
+
+func TestNotifierWatchdogPID(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "100000")
+
+	t.Setenv("WATCHDOG_PID", "1")
+	assert.Zero(t, NewNotifier().watchdog)
+
+	t.Setenv("WATCHDOG_PID", "")
+	assert.Equal(t, 100*time.Millisecond, NewNotifier().watchdog)
+}
+
+func TestNotifierNotHealthyWhenComponentFails(t *testing.T) {
+	n := NewNotifier()
+	h := n.Hooks()
+
+	h.OnStateChange(xrun.ComponentStatus{Name: "api", State: xrun.StateReady})
+	assert.False(t, n.healthy())
+
+	h.OnReady()
+	assert.True(t, n.healthy())
+
+	h.OnStateChange(xrun.ComponentStatus{Name: "api", State: xrun.StateFailed})
+	assert.False(t, n.healthy())
+}
+
+// fakeNotifySocket listens on a unix datagram socket set as NOTIFY_SOCKET
+// and returns the channel on which received notifications are sent
+func fakeNotifySocket(t *testing.T) <-chan string {
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	msgs := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			msgs <- string(buf[:n])
+		}
+	}()
+
+	return msgs
+}
+
+func waitFor(t *testing.T, msgs <-chan string, want string) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	var seen []string
+
+	for {
+		select {
+		case msg := <-msgs:
+			if msg == want {
+				return
+			}
+			seen = append(seen, msg)
+		case <-timeout:
+			t.Fatalf("notification %q not received, got: %s", want, strings.Join(seen, " | "))
+		}
+	}
+}
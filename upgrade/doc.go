@@ -0,0 +1,39 @@
+/*
+Package upgrade provides zero-downtime upgrades of a running binary. On upgrade,
+a new copy of the binary is started with all the listeners of the current process,
+and once the new process signals readiness the Manager of the current process is
+shutdown gracefully.
+
+	package main
+
+	import (
+		"context"
+		"net/http"
+		"os"
+		"os/signal"
+
+		"github.com/gojekfarm/xrun"
+		"github.com/gojekfarm/xrun/component"
+		"github.com/gojekfarm/xrun/upgrade"
+	)
+
+	func main() {
+		u := upgrade.New(upgrade.Options{})
+		m := xrun.NewManager(u.Hooks())
+
+		// Manager shuts down once u has handed over the listeners to the new process
+		_ = m.Add(xrun.Primary(u))
+		_ = m.Add(component.HTTPServer(component.HTTPServerOptions{
+			Server:      &http.Server{},
+			NewListener: u.Listener("http", component.TCPListener(":8080")),
+		}))
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := m.Run(ctx); err != nil {
+			os.Exit(1)
+		}
+	}
+*/
+package upgrade
@@ -0,0 +1,41 @@
+package upgrade_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+	"github.com/gojekfarm/xrun/upgrade"
+)
+
+func ExampleUpgrader() {
+	u := upgrade.New(upgrade.Options{
+		OnUpgradeError: func(err error) {
+			fmt.Println("upgrade failed:", err)
+		},
+	})
+	m := xrun.NewManager(u.Hooks())
+
+	// Manager shuts down once u has handed over the listeners to the new process
+	if err := m.Add(xrun.Primary(u)); err != nil {
+		panic(err)
+	}
+
+	if err := m.Add(component.HTTPServer(component.HTTPServerOptions{
+		Server:      &http.Server{},
+		NewListener: u.Listener("http", component.TCPListener(":8080")),
+	})); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := m.Run(ctx); err != nil {
+		os.Exit(1)
+	}
+}
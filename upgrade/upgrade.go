@@ -0,0 +1,372 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+)
+
+const (
+	envListeners = "XRUN_UPGRADE_LISTENERS"
+	envReadyFD   = "XRUN_UPGRADE_READY_FD"
+
+	// inheritedFDsStart is the first file descriptor passed with exec.Cmd.ExtraFiles
+	inheritedFDsStart = 3
+
+	defaultReadyTimeout = time.Minute
+)
+
+// ErrUpgradeInProgress is returned when an upgrade is triggered while another one is in progress
+var ErrUpgradeInProgress = errors.New("upgrade: an upgrade is already in progress")
+
+// Options holds options for Upgrader
+type Options struct {
+	// Signals trigger an upgrade, defaults to SIGHUP. xrun.ReloadOnSignal also defaults
+	// to SIGHUP, set different signals when using both, example: syscall.SIGUSR2.
+	Signals []os.Signal
+	// ReadyTimeout is the maximum time to wait for the new process to be ready,
+	// defaults to a minute
+	ReadyTimeout time.Duration
+	// Path is the binary to start on upgrade, defaults to the current executable
+	Path string
+	// Args are the arguments of the new process, default to the ones of the current process
+	Args []string
+	// OnUpgradeError is called when an upgrade triggered by a signal fails
+	OnUpgradeError func(error)
+}
+
+// Upgrader is a Component which upgrades the running binary on the configured Signals
+// or when Upgrade is called. It must be added to the Manager with xrun.Primary, so that
+// the Manager is shutdown once the new process is ready, and its Hooks must be passed to
+// the Manager, so that the new process signals readiness once all its components are ready.
+type Upgrader struct {
+	opts Options
+
+	inherited map[string]net.Listener
+	readyFile *os.File
+	readyOnce sync.Once
+
+	mu        sync.Mutex
+	names     []string
+	listeners map[string]net.Listener
+	upgrading bool
+	upgraded  chan struct{}
+	child     *os.Process
+}
+
+// New creates an Upgrader which inherits the listeners
+// passed by the parent process, when it was started by an upgrade
+func New(opts Options) *Upgrader {
+	if len(opts.Signals) == 0 {
+		opts.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	if opts.ReadyTimeout <= 0 {
+		opts.ReadyTimeout = defaultReadyTimeout
+	}
+
+	u := &Upgrader{
+		opts:      opts,
+		inherited: make(map[string]net.Listener),
+		listeners: make(map[string]net.Listener),
+		upgraded:  make(chan struct{}),
+	}
+
+	u.inherit()
+
+	return u
+}
+
+// HasParent reports whether the process was started by an upgrade
+func (u *Upgrader) HasParent() bool { return u.readyFile != nil }
+
+// Listener returns a component.ListenerFunc which returns the listener with the given name
+// inherited from the parent process, or creates it with nl when there is none.
+// The listener is passed to the new process on upgrade.
+func (u *Upgrader) Listener(name string, nl component.ListenerFunc) component.ListenerFunc {
+	return func() (net.Listener, error) {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		if _, ok := u.listeners[name]; ok {
+			return nil, fmt.Errorf("upgrade: listener %q is already in use", name)
+		}
+
+		l, ok := u.inherited[name]
+		if !ok {
+			var err error
+			if l, err = nl(); err != nil {
+				return nil, err
+			}
+		}
+
+		u.names = append(u.names, name)
+		u.listeners[name] = l
+
+		return l, nil
+	}
+}
+
+// Hooks returns the xrun.Hooks which signal readiness to the parent process
+func (u *Upgrader) Hooks() xrun.Hooks {
+	return xrun.Hooks{OnReady: u.signalReady}
+}
+
+// Run triggers an upgrade when one of the Signals is received,
+// it returns once an upgrade has succeeded or the context is closed
+func (u *Upgrader) Run(ctx context.Context) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, u.opts.Signals...)
+
+	defer signal.Stop(sigCh)
+
+	xrun.Ready(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-u.upgraded:
+			return nil
+		case <-sigCh:
+			if err := u.Upgrade(ctx); err != nil && u.opts.OnUpgradeError != nil {
+				u.opts.OnUpgradeError(err)
+			}
+		}
+	}
+}
+
+// Upgrade starts a new process with all the listeners and waits for it to be ready,
+// after which Run returns. The new process is killed if it is not ready within ReadyTimeout.
+func (u *Upgrader) Upgrade(ctx context.Context) error {
+	u.mu.Lock()
+
+	if u.upgrading {
+		u.mu.Unlock()
+
+		return ErrUpgradeInProgress
+	}
+
+	u.upgrading = true
+	u.mu.Unlock()
+
+	err := u.upgrade(ctx)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err != nil {
+		u.upgrading = false
+
+		return err
+	}
+
+	close(u.upgraded)
+
+	return nil
+}
+
+func (u *Upgrader) upgrade(ctx context.Context) error {
+	cmd, readyR, err := u.command()
+	if err != nil {
+		return err
+	}
+
+	defer readyR.Close()
+
+	if err := cmd.Start(); err != nil {
+		closeFiles(cmd.ExtraFiles)
+
+		return fmt.Errorf("upgrade: failed to start new process: %w", err)
+	}
+
+	closeFiles(cmd.ExtraFiles)
+
+	exited := make(chan error, 1)
+
+	go func() { exited <- cmd.Wait() }()
+
+	readyCh := make(chan error, 1)
+
+	go func() {
+		buf := make([]byte, 1)
+		if _, err := readyR.Read(buf); err != nil {
+			readyCh <- errors.New("upgrade: new process did not signal readiness")
+
+			return
+		}
+
+		readyCh <- nil
+	}()
+
+	timer := time.NewTimer(u.opts.ReadyTimeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-readyCh:
+	case waitErr := <-exited:
+		return fmt.Errorf("upgrade: new process exited before it was ready: %v", waitErr)
+	case <-timer.C:
+		err = fmt.Errorf("upgrade: new process was not ready within %s", u.opts.ReadyTimeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		_ = cmd.Process.Kill()
+
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.child = cmd.Process
+
+	// the socket files must outlive the listeners of this process
+	for _, l := range u.listeners {
+		if ul, ok := l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+
+	return nil
+}
+
+func (u *Upgrader) command() (*exec.Cmd, *os.File, error) {
+	path, args := u.opts.Path, u.opts.Args
+
+	if path == "" {
+		var err error
+		if path, err = os.Executable(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	files, names, err := u.listenerFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		closeFiles(files)
+
+		return nil, nil, err
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(environ(),
+		envListeners+"="+strings.Join(names, ":"),
+		envReadyFD+"="+strconv.Itoa(inheritedFDsStart+len(files)),
+	)
+
+	return cmd, readyR, nil
+}
+
+func (u *Upgrader) listenerFiles() ([]*os.File, []string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	files := make([]*os.File, 0, len(u.names))
+
+	for _, name := range u.names {
+		l := u.listeners[name]
+
+		fl, ok := l.(interface{ File() (*os.File, error) })
+		if !ok {
+			closeFiles(files)
+
+			return nil, nil, fmt.Errorf("upgrade: listener %q of type %T can't be passed on", name, l)
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			closeFiles(files)
+
+			return nil, nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	return files, append([]string(nil), u.names...), nil
+}
+
+// inherit picks up the listeners and the readiness pipe passed by the parent process
+func (u *Upgrader) inherit() {
+	fd, err := strconv.Atoi(os.Getenv(envReadyFD))
+	if err != nil {
+		return
+	}
+
+	names := strings.Split(os.Getenv(envListeners), ":")
+
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+
+		f := os.NewFile(uintptr(inheritedFDsStart+i), name)
+
+		if l, err := net.FileListener(f); err == nil {
+			u.inherited[name] = l
+		}
+
+		_ = f.Close()
+	}
+
+	u.readyFile = os.NewFile(uintptr(fd), "ready")
+
+	_ = os.Unsetenv(envListeners)
+	_ = os.Unsetenv(envReadyFD)
+}
+
+func (u *Upgrader) signalReady() {
+	if u.readyFile == nil {
+		return
+	}
+
+	u.readyOnce.Do(func() {
+		_, _ = u.readyFile.Write([]byte{1})
+		_ = u.readyFile.Close()
+	})
+}
+
+// environ returns the environment of the current process without the upgrade variables
+func environ() []string {
+	env := os.Environ()
+	out := env[:0:0]
+
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, envListeners+"=") && !strings.HasPrefix(kv, envReadyFD+"=") {
+			out = append(out, kv)
+		}
+	}
+
+	return out
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,162 @@
+package upgrade
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gojekfarm/xrun"
+	"github.com/gojekfarm/xrun/component"
+)
+
+// TestUpgradeHelperProcess is started as the new process by TestUpgrade
+func TestUpgradeHelperProcess(t *testing.T) {
+	switch os.Getenv("XRUN_UPGRADE_HELPER") {
+	case "serve":
+	case "exit":
+		return
+	default:
+		t.Skip("helper process for TestUpgrade")
+	}
+
+	u := New(Options{})
+	if !u.HasParent() {
+		t.Fatal("listeners were not inherited")
+	}
+
+	m := xrun.NewManager(u.Hooks())
+	_ = m.Add(u)
+	_ = m.Add(component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("child"))
+		})},
+		// the inherited listener is used, a new one must not be created
+		NewListener: u.Listener("http", component.TCPListener(":-1")),
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_ = m.Run(ctx)
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Setenv("XRUN_UPGRADE_HELPER", "serve")
+
+	u := New(Options{Args: []string{"-test.run=^TestUpgradeHelperProcess$"}})
+	assert.False(t, u.HasParent())
+
+	addrCh := make(chan net.Addr, 1)
+
+	m := xrun.NewManager(u.Hooks())
+	require.NoError(t, m.Add(xrun.Primary(u)))
+	require.NoError(t, m.Add(component.HTTPServer(component.HTTPServerOptions{
+		Server: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("parent"))
+		})},
+		NewListener: component.NotifyAddr(
+			u.Listener("http", component.TCPListener("localhost:0")),
+			func(addr net.Addr) { addrCh <- addr },
+		),
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- m.Run(ctx) }()
+
+	url := "http://" + (<-addrCh).String()
+	assert.Equal(t, "parent", get(t, url))
+
+	require.NoError(t, u.Upgrade(ctx))
+	t.Cleanup(func() { _ = u.child.Kill() })
+
+	// the parent Manager is shutdown once the new process is ready
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, "child", get(t, url))
+	assert.Equal(t, "child", get(t, url))
+}
+
+func TestUpgradeFailure(t *testing.T) {
+	t.Setenv("XRUN_UPGRADE_HELPER", "exit")
+
+	u := New(Options{Args: []string{"-test.run=^TestUpgradeHelperProcess$"}})
+	assert.Error(t, u.Upgrade(context.Background()))
+
+	// a failed upgrade can be retried
+	t.Setenv("XRUN_UPGRADE_HELPER", "")
+	u.opts.ReadyTimeout = 100 * time.Millisecond
+	u.opts.Args = []string{"-test.run=^TestUpgradeHelperProcess$", "-test.count=100"}
+	assert.Error(t, u.Upgrade(context.Background()))
+}
+
+func TestUpgradeFailureUnlinksSocket(t *testing.T) {
+	t.Setenv("XRUN_UPGRADE_HELPER", "exit")
+
+	path := filepath.Join(t.TempDir(), "xrun.sock")
+
+	u := New(Options{Args: []string{"-test.run=^TestUpgradeHelperProcess$"}})
+	l, err := u.Listener("unix", func() (net.Listener, error) { return net.Listen("unix", path) })()
+	require.NoError(t, err)
+
+	assert.Error(t, u.Upgrade(context.Background()))
+
+	// the socket file is kept only once the new process is ready
+	require.NoError(t, l.Close())
+	assert.NoFileExists(t, path)
+}
+
+func TestUpgradeInProgress(t *testing.T) {
+	u := New(Options{})
+	u.upgrading = true
+
+	assert.ErrorIs(t, u.Upgrade(context.Background()), ErrUpgradeInProgress)
+}
+
+func TestListenerAlreadyInUse(t *testing.T) {
+	u := New(Options{})
+	nl := u.Listener("http", component.TCPListener("localhost:0"))
+
+	l, err := nl()
+	require.NoError(t, err)
+	defer l.Close()
+
+	_, err = nl()
+	assert.EqualError(t, err, `upgrade: listener "http" is already in use`)
+}
+
+func TestListenerCannotBePassedOn(t *testing.T) {
+	u := New(Options{})
+
+	_, err := u.Listener("custom", component.Listener(customListener{}))()
+	require.NoError(t, err)
+
+	assert.EqualError(t, u.Upgrade(context.Background()),
+		`upgrade: listener "custom" of type upgrade.customListener can't be passed on`)
+}
+
+type customListener struct {
+	net.Listener
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	d, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(d)
+}
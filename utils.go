@@ -2,6 +2,7 @@ package xrun
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -19,3 +20,35 @@ func All(shutdownTimeout time.Duration, components ...Component) ComponentFunc {
 
 	return func(ctx context.Context) error { return m.Run(ctx) }
 }
+
+// Job is a utility function which runs primary along with its sidecars,
+// example: a metrics server next to a one-shot job. Once primary returns,
+// successfully or with an error, the sidecars are shutdown gracefully and
+// the result of primary is returned. Sidecar errors are returned only
+// when primary succeeds.
+func Job(primary Component, sidecars ...Component) ComponentFunc {
+	return func(ctx context.Context) error {
+		var primaryErr error
+
+		m := NewManager()
+
+		// Decorate keeps the wrappers of primary, such as Named or Watchdog, visible to m
+		_ = m.Add(Primary(Decorate(primary, func(ctx context.Context) error {
+			primaryErr = primary.Run(ctx)
+
+			return primaryErr
+		})))
+
+		for _, c := range sidecars {
+			_ = m.Add(c)
+		}
+
+		err := m.Run(ctx)
+
+		if primaryErr != nil && !errors.Is(primaryErr, context.Canceled) {
+			return primaryErr
+		}
+
+		return err
+	}
+}
@@ -2,7 +2,9 @@ package xrun
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,3 +24,108 @@ func TestAll(t *testing.T) {
 	cancel()
 	assert.NoError(t, <-errCh)
 }
+
+func TestAllWithPrimary(t *testing.T) {
+	r := All(NoTimeout,
+		Primary(ComponentFunc(func(ctx context.Context) error {
+			return nil
+		})),
+		ComponentFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}),
+	)
+
+	assert.NoError(t, r.Run(context.Background()))
+}
+
+func TestJob(t *testing.T) {
+	sidecarErr := errors.New("sidecar error")
+
+	testcases := []struct {
+		name     string
+		primary  Component
+		sidecars []Component
+		wantErr  error
+	}{
+		{
+			name:    "PrimarySucceeds",
+			primary: ComponentFunc(func(ctx context.Context) error { return nil }),
+			sidecars: []Component{
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				}),
+			},
+		},
+		{
+			name:    "PrimaryFails",
+			primary: ComponentFunc(func(ctx context.Context) error { return errors.New("job error") }),
+			sidecars: []Component{
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return sidecarErr
+				}),
+			},
+			wantErr: errors.New("job error"),
+		},
+		{
+			name:    "SidecarFailsOnShutdown",
+			primary: ComponentFunc(func(ctx context.Context) error { return nil }),
+			sidecars: []Component{
+				ComponentFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return sidecarErr
+				}),
+			},
+			wantErr: sidecarErr,
+		},
+		{
+			name: "SidecarFailsOnStart",
+			primary: ComponentFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+			sidecars: []Component{
+				ComponentFunc(func(ctx context.Context) error { return sidecarErr }),
+			},
+			wantErr: sidecarErr,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Job(tc.primary, tc.sidecars...).Run(context.Background())
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestJobPrimaryWrappers(t *testing.T) {
+	var name string
+
+	err := Job(Named("migrate", ComponentFunc(func(ctx context.Context) error {
+		name = ComponentName(ctx)
+
+		return nil
+	}))).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "migrate", name)
+
+	err = Job(Watchdog(WatchdogOptions{Timeout: 50 * time.Millisecond}, ComponentFunc(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+
+		return nil
+	}))).Run(context.Background())
+
+	assert.ErrorIs(t, err, ErrMissedHeartbeat)
+}